@@ -1,13 +1,20 @@
 package ast
 
 import (
+    "LexicalCalculator/function"
+    "LexicalCalculator/number"
     "LexicalCalculator/token"
     "errors"
     "fmt"
     "math"
 )
 
-var ErrZeroDivision = errors.New("error cannot use 0 as denominator")
+var (
+    ErrZeroDivision      = number.ErrZeroDivision
+    ErrNotAnInteger      = number.ErrNotAnInteger
+    ErrUndefinedVariable = errors.New("error undefined variable")
+    ErrAssignConstant    = errors.New("error cannot assign to a constant")
+)
 
 // Root should be the ast root of a calculator prompt.
 type Root struct {
@@ -15,85 +22,363 @@ type Root struct {
     EquationTokens []*token.Token
 }
 
-// Node is the general structure of all expressions in the equation.
-type Node struct {
-    Token      *token.Token
-    IsOperator bool
-    Operator   string
-    IsValue    bool
-    Value      float64
-    Left       *Node
-    Right      *Node
+// Node is a node of the tree of an equation.
+// The types of Nodes are Literal, Identifier, Unary, Binary, Assignment and Call.
+// Code working on trees either switches on the type of the Nodes or visits them with a Visitor.
+type Node interface {
+    // Source returns the token the Node was parsed from, nil if the Node was built without tokens.
+    Source() *token.Token
+    // String returns the S-expression of the Node, like '(+ 1.0000 (- x))'.
+    String() string
+    // Accept calls the method of the Visitor for the type of the Node, and returns its error.
+    Accept(v Visitor) error
+}
+
+// Visitor has a method for each type of Node, see Node.Accept.
+// The methods visit a single Node, a Visitor walking a tree visits the children itself.
+type Visitor interface {
+    VisitLiteral(n *Literal) error
+    VisitIdentifier(n *Identifier) error
+    VisitUnary(n *Unary) error
+    VisitBinary(n *Binary) error
+    VisitAssignment(n *Assignment) error
+    VisitCall(n *Call) error
+}
+
+// Literal is a number, like '2.5' or '3i'.
+type Literal struct {
+    Token *token.Token
+    Value number.Number
+}
+
+// Identifier is the Name of a variable or a constant.
+type Identifier struct {
+    Token *token.Token
+    Name  string
+}
+
+// Unary is a prefix operator and its operand, like '-x'.
+type Unary struct {
+    Token    *token.Token
+    Operator string
+    Operand  Node
+}
+
+// Binary is an infix operator and its operands, like 'x + 1'.
+type Binary struct {
+    Token    *token.Token
+    Operator string
+    Left     Node
+    Right    Node
+}
+
+// Assignment assigns the Value of an expression to the variable of the Target identifier, like 'rate = 0.07'.
+type Assignment struct {
+    Token  *token.Token
+    Target *Identifier
+    Value  Node
+}
+
+// Call calls the function of the given Name with the Args, like 'max(1, 2)'.
+type Call struct {
+    Token *token.Token
+    Name  string
+    Args  []Node
+}
+
+// NewLiteral creates a new Literal of a value.
+func NewLiteral(tok *token.Token, value number.Number) *Literal {
+    return &Literal{Token: tok, Value: value}
+}
+
+// NewIdentifier creates a new Identifier of a variable.
+func NewIdentifier(tok *token.Token, name string) *Identifier {
+    return &Identifier{Token: tok, Name: name}
+}
+
+// NewUnary creates a new Unary of a prefix operator.
+func NewUnary(tok *token.Token, operator string, operand Node) *Unary {
+    return &Unary{Token: tok, Operator: operator, Operand: operand}
+}
+
+// NewBinary creates a new Binary of an infix operator.
+func NewBinary(tok *token.Token, operator string, left Node, right Node) *Binary {
+    return &Binary{Token: tok, Operator: operator, Left: left, Right: right}
+}
+
+// NewAssignment creates a new Assignment of the value of an expression to the variable of an identifier.
+func NewAssignment(tok *token.Token, target *Identifier, value Node) *Assignment {
+    return &Assignment{Token: tok, Target: target, Value: value}
+}
+
+// NewCall creates a new Call of the function of the given name with the arguments.
+func NewCall(tok *token.Token, name string, args []Node) *Call {
+    return &Call{Token: tok, Name: name, Args: args}
+}
+
+func (n *Literal) Source() *token.Token    { return n.Token }
+func (n *Identifier) Source() *token.Token { return n.Token }
+func (n *Unary) Source() *token.Token      { return n.Token }
+func (n *Binary) Source() *token.Token     { return n.Token }
+func (n *Assignment) Source() *token.Token { return n.Token }
+func (n *Call) Source() *token.Token       { return n.Token }
+
+func (n *Literal) Accept(v Visitor) error    { return v.VisitLiteral(n) }
+func (n *Identifier) Accept(v Visitor) error { return v.VisitIdentifier(n) }
+func (n *Unary) Accept(v Visitor) error      { return v.VisitUnary(n) }
+func (n *Binary) Accept(v Visitor) error     { return v.VisitBinary(n) }
+func (n *Assignment) Accept(v Visitor) error { return v.VisitAssignment(n) }
+func (n *Call) Accept(v Visitor) error       { return v.VisitCall(n) }
+
+// String returns the value rounded to 4 decimal places.
+func (n *Literal) String() string {
+    return fmt.Sprintf("%.4f", n.Value)
+}
+
+// String returns the name.
+func (n *Identifier) String() string {
+    return n.Name
 }
 
-// String returns the S-expression of a Node.
-// S-expression
-func (n *Node) String() string {
-    if n.Left == nil && n.Right == nil {
-        return fmt.Sprintf("%.4f", n.Value)
-    } else if n.Left == nil && n.Right != nil {
-        return fmt.Sprintf("(%s %s %s)", n.Operator, "0", n.Right.String())
-    } else {
-        return fmt.Sprintf("(%s %s %s)", n.Operator, n.Left.String(), n.Right.String())
+// String returns the S-expression of the operator, like '(- x)'.
+func (n *Unary) String() string {
+    return fmt.Sprintf("(%s %s)", n.Operator, sExpression(n.Operand))
+}
+
+// String returns the S-expression of the operator, like '(+ x 1.0000)'.
+func (n *Binary) String() string {
+    return fmt.Sprintf("(%s %s %s)", n.Operator, sExpression(n.Left), sExpression(n.Right))
+}
+
+// String returns the S-expression of the assignment, like '(= rate 0.0700)'.
+func (n *Assignment) String() string {
+    return fmt.Sprintf("(= %s %s)", n.Target.Name, sExpression(n.Value))
+}
+
+// String returns the S-expression of the call, like '(max 1.0000 2.0000)'.
+func (n *Call) String() string {
+    s := "(" + n.Name
+    for _, arg := range n.Args {
+        s += " " + sExpression(arg)
     }
+    return s + ")"
 }
 
-// New creates a new Node.
-func New(tok *token.Token, value float64, isValue bool, operation string, isOperator bool, leftChild *Node, rightNode *Node) *Node {
-    return &Node{
-        Token:      tok,
-        IsOperator: isOperator,
-        Operator:   operation,
-        IsValue:    isValue,
-        Value:      value,
-        Left:       leftChild,
-        Right:      rightNode,
+// sExpression returns the S-expression of a Node, missing operands of incomplete trees are 0.
+func sExpression(n Node) string {
+    if n == nil {
+        return "0"
+    }
+    return n.String()
+}
+
+// Children returns the children of a Node from left to right.
+// The target of an Assignment is its first child, Literals and Identifiers have no children.
+func Children(n Node) []Node {
+    switch n := n.(type) {
+    case *Unary:
+        return []Node{n.Operand}
+    case *Binary:
+        return []Node{n.Left, n.Right}
+    case *Assignment:
+        return []Node{n.Target, n.Value}
+    case *Call:
+        return n.Args
+    default:
+        return nil
     }
 }
 
 // Evaluate evaluates the current node and return the result of the equation.
-func Evaluate(equationNode *Node) (float64, error) {
-    // Scenarios
-    // 1. 6 ( One single integer )
-    // 2. -6 ( Negative integer )
-    // 3. 6 + 5 ( Binary Expression )
-    // 4. nil node ( should be 0 )
-    // 5. 5 + 2 * 3 ( '*' operator has higher precedence than '+' )
-
-    // If node is neither an operator nor a value, it is 0.
-    // Consider ' + 5 '
-    // it's   +
-    //       / \
-    //     nil  5
-    // the value of the left child should be considered as 0.
-    if equationNode == nil {
-        return 0, nil
-    }
-
-    if equationNode.IsValue {
-        return equationNode.Value, nil
-    }
-
-    if equationNode.IsOperator {
-        left, err := Evaluate(equationNode.Left)
-        right, err := Evaluate(equationNode.Right)
-
-        switch equationNode.Operator {
-        case "+":
-            return left + right, err
-        case "-":
-            return left - right, err
-        case "*":
-            return left * right, err
-        case "/":
-            if right == 0 {
-                return 0, ErrZeroDivision
-            }
-            return left / right, err
-        case "^":
-            return math.Pow(left, right), err
+// Equations with variables can't be evaluated without an Environment, see EvaluateIn.
+func Evaluate(equationNode Node) (number.Number, error) {
+    return EvaluateIn(equationNode, nil)
+}
+
+// EvaluateIn evaluates the current node with the variables of an Environment and return the result of the equation.
+// Assignments store the assigned value in the Environment.
+// EvaluateIn doesn't do any arithmetic itself, every operator is dispatched to the number.Number of its left operand,
+// so the result is of the same backend as the values.
+// A missing node, like the operand of an incomplete operator, is 0.
+func EvaluateIn(equationNode Node, env *Environment) (number.Number, error) {
+    e := &evaluator{env: env}
+    return e.evaluate(equationNode)
+}
+
+// evaluator is the Visitor evaluating the Nodes of a tree, each visit stores the value of the visited Node.
+type evaluator struct {
+    env   *Environment
+    value number.Number
+}
+
+// evaluate visits a Node and returns its value.
+func (e *evaluator) evaluate(n Node) (number.Number, error) {
+    if n == nil {
+        return number.Float(0), nil
+    }
+    if err := n.Accept(e); err != nil {
+        return nil, err
+    }
+    return e.value, nil
+}
+
+func (e *evaluator) VisitLiteral(n *Literal) error {
+    e.value = n.Value
+    return nil
+}
+
+func (e *evaluator) VisitIdentifier(n *Identifier) error {
+    value, ok := e.env.Get(n.Name)
+    if !ok {
+        return fmt.Errorf("%w '%s'", ErrUndefinedVariable, n.Name)
+    }
+    e.value = value
+    return nil
+}
+
+func (e *evaluator) VisitUnary(n *Unary) error {
+    operand, err := e.evaluate(n.Operand)
+    if err != nil {
+        return err
+    }
+    switch n.Operator {
+    case "-":
+        operand, err = operand.Neg()
+    case "~":
+        operand, err = bitwise(n.Operator, operand, nil)
+    }
+    e.value = operand
+    return err
+}
+
+func (e *evaluator) VisitBinary(n *Binary) error {
+    // The right operand is evaluated first, so its errors are reported before the ones of the left operand.
+    right, err := e.evaluate(n.Right)
+    if err != nil {
+        return err
+    }
+    left, err := e.evaluate(n.Left)
+    if err != nil {
+        return err
+    }
+
+    // The complex evaluation path: operations on complex numbers, or with results that are only complex,
+    // like the square root of a negative number, are evaluated with both operands converted to complex numbers.
+    if isComplex(left) || isComplex(right) || (n.Operator == "^" && hasComplexPower(left, right)) {
+        left, _ = number.ComplexBackend.Convert(left)
+        right, _ = number.ComplexBackend.Convert(right)
+    }
+
+    switch n.Operator {
+    case "+":
+        e.value, err = left.Add(right)
+    case "-":
+        e.value, err = left.Sub(right)
+    case "*":
+        e.value, err = left.Mul(right)
+    case "/":
+        e.value, err = left.Quo(right)
+    case "^":
+        e.value, err = left.Pow(right)
+    case "//":
+        e.value, err = left.FloorQuo(right)
+    case "%":
+        e.value, err = left.Mod(right)
+    case "rem":
+        e.value, err = left.Rem(right)
+    case "&", "|", "xor", "<<", ">>":
+        e.value, err = bitwise(n.Operator, left, right)
+    default:
+        e.value = number.Float(0)
+    }
+    return err
+}
+
+func (e *evaluator) VisitAssignment(n *Assignment) error {
+    value, err := e.evaluate(n.Value)
+    if err != nil {
+        return err
+    }
+    if e.env == nil {
+        return fmt.Errorf("%w '%s'", ErrUndefinedVariable, n.Target.Name)
+    }
+    if e.env.IsConstant(n.Target.Name) {
+        return fmt.Errorf("%w '%s'", ErrAssignConstant, n.Target.Name)
+    }
+    e.env.Set(n.Target.Name, value)
+    e.value = value
+    return nil
+}
+
+// VisitCall evaluates the arguments of a call and calls the function with them.
+func (e *evaluator) VisitCall(n *Call) error {
+    f, ok := e.env.Function(n.Name)
+    if !ok {
+        return fmt.Errorf("%w '%s'", function.ErrUnknownFunction, n.Name)
+    }
+    if err := f.CheckArity(len(n.Args)); err != nil {
+        return err
+    }
+
+    args := make([]number.Number, len(n.Args))
+    for i, arg := range n.Args {
+        value, err := e.evaluate(arg)
+        if err != nil {
+            return err
         }
+        args[i] = value
     }
+    value, err := f.Call(args)
+    e.value = value
+    return err
+}
+
+// bitwise evaluates a bitwise operator on the 64-bit integers of its operands, the right operand is nil for '~'.
+// Operands which aren't integers are rejected instead of truncated, the result has the backend of the left operand.
+func bitwise(operator string, left number.Number, right number.Number) (number.Number, error) {
+    operands := make([]number.Int, 0, 2)
+    for _, operand := range []number.Number{left, right} {
+        if operand == nil {
+            continue
+        }
+        i, err := number.IntBackend.Convert(operand)
+        if err != nil {
+            return nil, fmt.Errorf("%w: '%s' needs 64-bit integer operands, got %v", err, operator, operand)
+        }
+        operands = append(operands, i.(number.Int))
+    }
+
+    var result number.Number
+    var err error
+    switch operator {
+    case "~":
+        result = operands[0].Not()
+    case "&":
+        result, err = operands[0].And(operands[1])
+    case "|":
+        result, err = operands[0].Or(operands[1])
+    case "xor":
+        result, err = operands[0].Xor(operands[1])
+    case "<<":
+        result, err = operands[0].Shl(operands[1])
+    case ">>":
+        result, err = operands[0].Shr(operands[1])
+    }
+    if err != nil {
+        return nil, err
+    }
+    return number.BackendOf(left).Convert(result)
+}
+
+// isComplex checks whether a number is a complex number.
+func isComplex(n number.Number) bool {
+    _, ok := n.(number.Complex)
+    return ok
+}
 
-    return 0, nil
+// hasComplexPower checks whether the power of a real base is a complex number, which is when the base is negative
+// and the exponent isn't an integer, like (-4) ^ 0.5.
+func hasComplexPower(base number.Number, exponent number.Number) bool {
+    e := exponent.Float64()
+    return base.Float64() < 0 && e != math.Trunc(e)
 }
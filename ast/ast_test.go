@@ -1,76 +1,79 @@
 package ast
 
 import (
+    "LexicalCalculator/function"
+    "LexicalCalculator/number"
     "LexicalCalculator/support"
+    "errors"
+    "math/big"
     "testing"
 )
 
 func TestNode_String(t *testing.T) {
     testCases := []struct {
-        node *Node
+        node Node
         str  string
     }{
         {
-            node: New(nil, 1, true, "", false, nil, nil),
+            node: NewLiteral(nil, number.Float(1)),
             str:  "1.0000",
         },
         {
-            node: New(nil, 0, false, "+", true,
-                nil,
-                New(nil, 2, true, "", false, nil, nil),
+            node: NewUnary(nil, "+",
+                NewLiteral(nil, number.Float(2)),
             ),
-            str: "(+ 0 2.0000)",
+            str: "(+ 2.0000)",
         },
         {
-            node: New(nil, 0, false, "+", true,
-                New(nil, 3, true, "", false, nil, nil),
-                New(nil, 2, true, "", false, nil, nil),
+            node: NewBinary(nil, "+",
+                NewLiteral(nil, number.Float(3)),
+                NewLiteral(nil, number.Float(2)),
             ),
             str: "(+ 3.0000 2.0000)",
         },
         {
-            node: New(nil, 0, false, "-", true,
-                New(nil, 3, true, "", false, nil, nil),
-                New(nil, 2, true, "", false, nil, nil),
+            node: NewBinary(nil, "-",
+                NewLiteral(nil, number.Float(3)),
+                NewLiteral(nil, number.Float(2)),
             ),
             str: "(- 3.0000 2.0000)",
         },
         {
-            node: New(nil, 0, false, "*", true,
-                New(nil, 3, true, "", false, nil, nil),
-                New(nil, 2, true, "", false, nil, nil),
+            node: NewBinary(nil, "*",
+                NewLiteral(nil, number.Float(3)),
+                NewLiteral(nil, number.Float(2)),
             ),
             str: "(* 3.0000 2.0000)",
         },
         {
-            node: New(nil, 0, false, "/", true,
-                New(nil, 3, true, "", false, nil, nil),
-                New(nil, 2, true, "", false, nil, nil),
+            node: NewBinary(nil, "/",
+                NewLiteral(nil, number.Float(3)),
+                NewLiteral(nil, number.Float(2)),
             ),
             str: "(/ 3.0000 2.0000)",
         },
         {
-            node: New(nil, 0, false, "+", true,
-                New(nil, 5, true, "", false, nil, nil),
-                New(nil, 2, false, "*", true,
-                    New(nil, 2, true, "", false, nil, nil),
-                    New(nil, 3, true, "", false, nil, nil),
+            node: NewBinary(nil, "+",
+                NewLiteral(nil, number.Float(5)),
+                NewBinary(nil, "*",
+                    NewLiteral(nil, number.Float(2)),
+                    NewLiteral(nil, number.Float(3)),
                 ),
             ),
             str: "(+ 5.0000 (* 2.0000 3.0000))",
         },
         {
-            node: New(nil, 0, false, "-", true,
-                New(nil, 0, false, "+", true,
-                    New(nil, 1, true, "", false, nil, nil),
-                    New(nil, 0, false, "*", true,
-                        New(nil, 2, true, "", false, nil, nil),
-                        New(nil, 5, true, "", false, nil, nil),
+            node: NewBinary(nil, "-",
+                NewBinary(nil, "+",
+                    NewLiteral(nil, number.Float(1)),
+                    NewBinary(nil, "*",
+                        NewLiteral(nil, number.Float(2)),
+                        NewLiteral(nil, number.Float(5)),
                     ),
                 ),
-                New(nil, 0, false, "*", true,
-                    New(nil, 3, true, "", false, nil, nil),
-                    New(nil, 2, true, "", false, nil, nil),
+                NewBinary(nil, "*",
+                    NewLiteral(nil, number.Float(3)),
+                    NewLiteral(nil, number.Float(2)),
                 ),
             ),
             str: "(- (+ 1.0000 (* 2.0000 5.0000)) (* 3.0000 2.0000))",
@@ -86,17 +89,17 @@ func TestNode_String(t *testing.T) {
 }
 
 func TestEvaluate(t *testing.T) {
-    leaf1 := New(nil, 6, true, "", false, nil, nil)
-    leaf2 := New(nil, 3, true, "", false, nil, nil)
-    leaf3 := New(nil, 5, true, "", false, nil, nil)
-    leaf4 := New(nil, 0, false, "+", true, leaf1, leaf2)
-    leaf5 := New(nil, 0, false, "*", true, leaf1, leaf2)
-    leaf6 := New(nil, 0, false, "^", true, leaf1, leaf2)
+    leaf1 := NewLiteral(nil, number.Float(6))
+    leaf2 := NewLiteral(nil, number.Float(3))
+    leaf3 := NewLiteral(nil, number.Float(5))
+    leaf4 := NewBinary(nil, "+", leaf1, leaf2)
+    leaf5 := NewBinary(nil, "*", leaf1, leaf2)
+    leaf6 := NewBinary(nil, "^", leaf1, leaf2)
 
     testCases := []struct {
-        root   *Node
-        left   *Node
-        right  *Node
+        root   Node
+        left   Node
+        right  Node
         result float64
     }{
         {
@@ -115,77 +118,77 @@ func TestEvaluate(t *testing.T) {
         },
         {
             // -6
-            root:   New(nil, 0, false, "-", true, nil, leaf1),
+            root:   NewUnary(nil, "-", leaf1),
             left:   nil,
             right:  leaf1,
             result: -6,
         },
         {
             // 6 + 3
-            root:   New(nil, 0, false, "+", true, leaf1, leaf2),
+            root:   NewBinary(nil, "+", leaf1, leaf2),
             left:   leaf1,
             right:  leaf2,
             result: 9,
         },
         {
             // 6 - 3
-            root:   New(nil, 0, false, "-", true, leaf1, leaf2),
+            root:   NewBinary(nil, "-", leaf1, leaf2),
             left:   leaf1,
             right:  leaf2,
             result: 3,
         },
         {
             // 6 * 3
-            root:   New(nil, 0, false, "*", true, leaf1, leaf2),
+            root:   NewBinary(nil, "*", leaf1, leaf2),
             left:   leaf1,
             right:  leaf2,
             result: 18,
         },
         {
             // 6 / 3
-            root:   New(nil, 0, false, "/", true, leaf1, leaf2),
+            root:   NewBinary(nil, "/", leaf1, leaf2),
             left:   leaf1,
             right:  leaf2,
             result: 2,
         },
         {
             // 6 / 3
-            root:   New(nil, 0, false, "/", true, leaf1, leaf2),
+            root:   NewBinary(nil, "/", leaf1, leaf2),
             left:   leaf1,
             right:  leaf2,
             result: 2,
         },
         {
             // 6 ^ 3
-            root:   New(nil, 0, false, "^", true, leaf1, leaf2),
+            root:   NewBinary(nil, "^", leaf1, leaf2),
             left:   leaf1,
             right:  leaf2,
             result: 216,
         },
         {
             // 5 + 6 + 3
-            root:   New(nil, 0, false, "+", true, leaf3, leaf4),
+            root:   NewBinary(nil, "+", leaf3, leaf4),
             left:   leaf3,
             right:  leaf4,
             result: 14,
         },
         {
             // 5 + 6 * 3
-            root:   New(nil, 0, false, "+", true, leaf3, leaf5),
+            root:   NewBinary(nil, "+", leaf3, leaf5),
             left:   leaf3,
             right:  leaf5,
             result: 23,
         },
         {
             // 5 + 6 ^ 3
-            root:   New(nil, 0, false, "+", true, leaf3, leaf6),
+            root:   NewBinary(nil, "+", leaf3, leaf6),
             left:   leaf3,
             right:  leaf6,
             result: 221,
         },
         {
             // 5 * 6 ^ 3
-            root:   New(nil, 0, false, "*", true, leaf3, leaf6),
+            root:   NewBinary(nil, "*", leaf3, leaf6),
             left:   leaf3,
             right:  leaf6,
             result: 1080,
@@ -198,8 +201,176 @@ func TestEvaluate(t *testing.T) {
             t.Errorf("Error evaluating node, got err: %v.\n", err)
         }
 
-        if !support.AlmostEqual(re, tc.result, 0.0001) {
-            t.Errorf("Error evaluating node: expected %f, got %f.\n", tc.result, re)
+        if !support.AlmostEqual(re.Float64(), tc.result, 0.0001) {
+            t.Errorf("Error evaluating node: expected %f, got %s.\n", tc.result, re)
+        }
+    }
+}
+
+func TestEvaluate_Number(t *testing.T) {
+    testCases := []struct {
+        root   Node
+        result string
+        err    error
+    }{
+        {
+            // 8 / 2 with int64
+            root:   NewBinary(nil, "/", NewLiteral(nil, number.Int(8)), NewLiteral(nil, number.Int(2))),
+            result: "4",
+        },
+        {
+            // 7 / 2 with int64
+            root: NewBinary(nil, "/", NewLiteral(nil, number.Int(7)), NewLiteral(nil, number.Int(2))),
+            err:  number.ErrNotAnInteger,
+        },
+        {
+            // 2 ^ 63 with int64
+            root: NewBinary(nil, "^", NewLiteral(nil, number.Int(2)), NewLiteral(nil, number.Int(63))),
+            err:  number.ErrOverflow,
+        },
+        {
+            // -(3 + 4i) * (1 - 2i) with complex128
+            root: NewUnary(nil, "-",
+                NewBinary(nil, "*", NewLiteral(nil, number.Complex(3+4i)), NewLiteral(nil, number.Complex(1-2i))),
+            ),
+            result: "-11 + 2i",
+        },
+        {
+            // 2 + 1i with float64 promoted to complex128
+            root:   NewBinary(nil, "+", NewLiteral(nil, number.Float(2)), NewLiteral(nil, number.Complex(1i))),
+            result: "2 + 1i",
+        },
+        {
+            // (-9) ^ 0.5 with float64 promoted to complex128
+            root:   NewBinary(nil, "^", NewLiteral(nil, number.Float(-9)), NewLiteral(nil, number.Float(0.5))),
+            result: "0 + 3i",
+        },
+        {
+            // 1 / 0 with big.Rat
+            root: NewBinary(nil, "/", NewLiteral(nil, number.NewRat(big.NewRat(1, 1))), NewLiteral(nil, number.NewRat(new(big.Rat)))),
+            err:  ErrZeroDivision,
+        },
+        {
+            // -7 % 2 with big.Rat, the modulo has the sign of the divisor
+            root:   NewBinary(nil, "%", NewLiteral(nil, number.NewRat(big.NewRat(-7, 1))), NewLiteral(nil, number.NewRat(big.NewRat(2, 1)))),
+            result: "1",
+        },
+        {
+            // -7 rem 2 with int64, the remainder has the sign of the dividend
+            root:   NewBinary(nil, "rem", NewLiteral(nil, number.Int(-7)), NewLiteral(nil, number.Int(2))),
+            result: "-1",
+        },
+        {
+            // 2i // 1 with complex128
+            root: NewBinary(nil, "//", NewLiteral(nil, number.Complex(2i)), NewLiteral(nil, number.Float(1))),
+            err:  number.ErrNotReal,
+        },
+        {
+            // ~5 & 12 with big.Rat
+            root:   NewBinary(nil, "&", NewUnary(nil, "~", NewLiteral(nil, number.NewRat(big.NewRat(5, 1)))), NewLiteral(nil, number.NewRat(big.NewRat(12, 1)))),
+            result: "8",
+        },
+        {
+            // 3 << 4 with float64
+            root:   NewBinary(nil, "<<", NewLiteral(nil, number.Float(3)), NewLiteral(nil, number.Float(4))),
+            result: "48",
+        },
+        {
+            // 2.5 | 1 with float64, which isn't truncated
+            root: NewBinary(nil, "|", NewLiteral(nil, number.Float(2.5)), NewLiteral(nil, number.Float(1))),
+            err:  ErrNotAnInteger,
+        },
+        {
+            // 1i xor 1 with complex128
+            root: NewBinary(nil, "xor", NewLiteral(nil, number.Complex(1i)), NewLiteral(nil, number.Int(1))),
+            err:  number.ErrNotReal,
+        },
+        {
+            // 1 // 0 with float64
+            root: NewBinary(nil, "//", NewLiteral(nil, number.Float(1)), NewLiteral(nil, number.Float(0))),
+            err:  ErrZeroDivision,
+        },
+    }
+
+    for _, tc := range testCases {
+        re, err := Evaluate(tc.root)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error evaluating node: expected error %v, got %v.\n", tc.err, err)
+            continue
+        }
+        if err == nil && re.String() != tc.result {
+            t.Errorf("Error evaluating node: expected %s, got %s.\n", tc.result, re)
+        }
+    }
+}
+
+func TestEvaluate_Call(t *testing.T) {
+    // max(2, sqrt(16))
+    call := NewCall(nil, "max", []Node{
+        NewLiteral(nil, number.Float(2)),
+        NewCall(nil, "sqrt", []Node{NewLiteral(nil, number.Float(16))}),
+    })
+    if call.String() != "(max 2.0000 (sqrt 16.0000))" {
+        t.Errorf("Error call String: expected (max 2.0000 (sqrt 16.0000)), got %s.\n", call.String())
+    }
+
+    re, err := Evaluate(call)
+    if err != nil || re.Float64() != 4 {
+        t.Errorf("Error evaluating %s: expected 4, got %v, error %v.\n", call, re, err)
+    }
+
+    unknown := NewCall(nil, "foo", nil)
+    if _, err := Evaluate(unknown); !errors.Is(err, function.ErrUnknownFunction) {
+        t.Errorf("Error evaluating %s: expected error %s, got %v.\n", unknown, function.ErrUnknownFunction, err)
+    }
+
+    wrongArity := NewCall(nil, "sqrt", nil)
+    if _, err := Evaluate(wrongArity); !errors.Is(err, function.ErrArity) {
+        t.Errorf("Error evaluating %s: expected error %s, got %v.\n", wrongArity, function.ErrArity, err)
+    }
+}
+
+// kindCounter counts the visited Nodes of each type in a tree.
+type kindCounter struct {
+    counts map[string]int
+}
+
+func (c *kindCounter) visit(kind string, n Node) error {
+    c.counts[kind]++
+    for _, child := range Children(n) {
+        if err := child.Accept(c); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (c *kindCounter) VisitLiteral(n *Literal) error       { return c.visit("literal", n) }
+func (c *kindCounter) VisitIdentifier(n *Identifier) error { return c.visit("identifier", n) }
+func (c *kindCounter) VisitUnary(n *Unary) error           { return c.visit("unary", n) }
+func (c *kindCounter) VisitBinary(n *Binary) error         { return c.visit("binary", n) }
+func (c *kindCounter) VisitAssignment(n *Assignment) error { return c.visit("assignment", n) }
+func (c *kindCounter) VisitCall(n *Call) error             { return c.visit("call", n) }
+
+func TestNode_Accept(t *testing.T) {
+    // y = -max(x, 2) ^ 2
+    two := NewLiteral(nil, number.Float(2))
+    x := NewIdentifier(nil, "x")
+    node := NewAssignment(nil, NewIdentifier(nil, "y"),
+        NewUnary(nil, "-", NewBinary(nil, "^", NewCall(nil, "max", []Node{x, two}), two)),
+    )
+    if node.String() != "(= y (- (^ (max x 2.0000) 2.0000)))" {
+        t.Errorf("Error transforming code into S-expression: expected (= y (- (^ (max x 2.0000) 2.0000))), got %s.\n", node)
+    }
+
+    c := &kindCounter{counts: make(map[string]int)}
+    if err := node.Accept(c); err != nil {
+        t.Fatalf("Error visiting %s: got error %v.\n", node, err)
+    }
+    expected := map[string]int{"assignment": 1, "identifier": 2, "unary": 1, "binary": 1, "call": 1, "literal": 2}
+    for kind, count := range expected {
+        if c.counts[kind] != count {
+            t.Errorf("Error visiting %s: expected %d %s nodes, got %d.\n", node, count, kind, c.counts[kind])
         }
     }
 }
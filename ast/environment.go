@@ -0,0 +1,104 @@
+package ast
+
+import (
+    "LexicalCalculator/function"
+    "LexicalCalculator/number"
+    "sort"
+)
+
+// Environment holds the variables and constants of a calculator session, and the functions it can call.
+// Functions of the Environment take precedence over the built-in functions of the same name.
+// A nil Environment has no variables, but it can still call the built-in functions.
+type Environment struct {
+    variables map[string]number.Number
+    constants map[string]number.Number
+    functions map[string]*function.Function
+}
+
+// NewEnvironment creates a new Environment without variables.
+func NewEnvironment() *Environment {
+    return &Environment{
+        variables: make(map[string]number.Number),
+        constants: make(map[string]number.Number),
+        functions: make(map[string]*function.Function),
+    }
+}
+
+// Get returns the value of a variable or a constant, and whether it's defined.
+func (e *Environment) Get(name string) (number.Number, bool) {
+    if e == nil {
+        return nil, false
+    }
+    if value, ok := e.variables[name]; ok {
+        return value, true
+    }
+    value, ok := e.constants[name]
+    return value, ok
+}
+
+// Set defines a variable, or replaces its value if it's already defined.
+// Constants can't be replaced, see IsConstant.
+func (e *Environment) Set(name string, value number.Number) {
+    e.variables[name] = value
+}
+
+// SetConstant defines a constant, which equations can't assign to.
+// A variable of the same name is removed.
+func (e *Environment) SetConstant(name string, value number.Number) {
+    delete(e.variables, name)
+    e.constants[name] = value
+}
+
+// IsConstant checks whether a name is a constant.
+func (e *Environment) IsConstant(name string) bool {
+    if e == nil {
+        return false
+    }
+    _, ok := e.constants[name]
+    return ok
+}
+
+// Convert converts the variables and constants to a backend.
+// Those the backend can't represent are kept as they are.
+func (e *Environment) Convert(backend number.Backend) {
+    for _, values := range []map[string]number.Number{e.variables, e.constants} {
+        for name, value := range values {
+            if converted, err := backend.Convert(value); err == nil {
+                values[name] = converted
+            }
+        }
+    }
+}
+
+// Delete removes a variable.
+func (e *Environment) Delete(name string) {
+    delete(e.variables, name)
+}
+
+// Names returns the names of all defined variables in alphabetical order.
+func (e *Environment) Names() []string {
+    if e == nil {
+        return nil
+    }
+    names := make([]string, 0, len(e.variables))
+    for name := range e.variables {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// SetFunction defines a function, or replaces it if it's already defined.
+func (e *Environment) SetFunction(f *function.Function) {
+    e.functions[f.Name] = f
+}
+
+// Function returns the function of the given name, and whether it exists.
+func (e *Environment) Function(name string) (*function.Function, bool) {
+    if e != nil {
+        if f, ok := e.functions[name]; ok {
+            return f, true
+        }
+    }
+    return function.Lookup(name)
+}
@@ -0,0 +1,101 @@
+package ast
+
+import (
+    "LexicalCalculator/function"
+    "LexicalCalculator/number"
+    "errors"
+    "testing"
+)
+
+func TestEnvironment(t *testing.T) {
+    env := NewEnvironment()
+    env.Set("rate", number.Float(0.07))
+    env.Set("principal", number.Float(1000))
+
+    if value, ok := env.Get("rate"); !ok || value.String() != "0.07" {
+        t.Errorf("Error getting variable rate: expected 0.07, got %v.\n", value)
+    }
+
+    env.Delete("rate")
+    if _, ok := env.Get("rate"); ok {
+        t.Errorf("Error deleting variable rate: expected undefined variable.\n")
+    }
+
+    names := env.Names()
+    if len(names) != 1 || names[0] != "principal" {
+        t.Errorf("Error variable names: expected [principal], got %v.\n", names)
+    }
+
+    var nilEnv *Environment
+    if _, ok := nilEnv.Get("principal"); ok {
+        t.Errorf("Error getting variable from nil Environment: expected undefined variable.\n")
+    }
+}
+
+func TestEvaluateIn(t *testing.T) {
+    env := NewEnvironment()
+    x := NewIdentifier(nil, "x")
+
+    // x = 2 * 3
+    assignment := NewAssignment(nil, x, NewBinary(nil, "*", NewLiteral(nil, number.Float(2)), NewLiteral(nil, number.Float(3))))
+    if assignment.String() != "(= x (* 2.0000 3.0000))" {
+        t.Errorf("Error transforming assignment into S-expression: got %s.\n", assignment)
+    }
+
+    re, err := EvaluateIn(assignment, env)
+    if err != nil || re.String() != "6" {
+        t.Errorf("Error evaluating assignment: expected 6, got %v, error %v.\n", re, err)
+    }
+
+    // x + 1
+    sum := NewBinary(nil, "+", x, NewLiteral(nil, number.Float(1)))
+    re, err = EvaluateIn(sum, env)
+    if err != nil || re.String() != "7" {
+        t.Errorf("Error evaluating x + 1: expected 7, got %v, error %v.\n", re, err)
+    }
+
+    // Without an Environment, variables are undefined.
+    if _, err = Evaluate(sum); !errors.Is(err, ErrUndefinedVariable) {
+        t.Errorf("Error evaluating x + 1 without Environment: expected error %v, got %v.\n", ErrUndefinedVariable, err)
+    }
+}
+
+func TestEnvironment_Constants(t *testing.T) {
+    env := NewEnvironment()
+    env.Set("g", number.Float(10))
+    env.SetConstant("g", number.Float(9.81))
+
+    if value, ok := env.Get("g"); !ok || !env.IsConstant("g") || value.String() != "9.81" {
+        t.Errorf("Error getting constant g: expected 9.81, got %v.\n", value)
+    }
+
+    assignment := NewAssignment(nil, NewIdentifier(nil, "g"), NewLiteral(nil, number.Float(1)))
+    if _, err := EvaluateIn(assignment, env); !errors.Is(err, ErrAssignConstant) {
+        t.Errorf("Error assigning to constant g: expected error %s, got %v.\n", ErrAssignConstant, err)
+    }
+
+    env.Convert(number.RatBackend)
+    if value, _ := env.Get("g"); value.String() != "9.81" {
+        t.Errorf("Error converting constant g: expected 9.81, got %v.\n", value)
+    } else if _, ok := value.(number.Rat); !ok {
+        t.Errorf("Error converting constant g: expected a number.Rat, got %T.\n", value)
+    }
+}
+
+func TestEnvironment_Function(t *testing.T) {
+    env := NewEnvironment()
+    env.SetFunction(&function.Function{Name: "sqrt", MinArgs: 1, MaxArgs: 1, Call: func(args []number.Number) (number.Number, error) {
+        return number.Float(-1), nil
+    }})
+
+    // Functions of the Environment shadow the built-in functions.
+    re, err := EvaluateIn(NewCall(nil, "sqrt", []Node{NewLiteral(nil, number.Float(4))}), env)
+    if err != nil || re.Float64() != -1 {
+        t.Errorf("Error calling sqrt of the Environment: expected -1, got %v, error %v.\n", re, err)
+    }
+
+    var nilEnv *Environment
+    if _, ok := nilEnv.Function("sqrt"); !ok {
+        t.Errorf("Error looking up sqrt in nil Environment: expected the built-in function.\n")
+    }
+}
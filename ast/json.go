@@ -0,0 +1,302 @@
+package ast
+
+import (
+    "LexicalCalculator/number"
+    "LexicalCalculator/token"
+    "bytes"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "math/big"
+    "strconv"
+)
+
+// EncodingVersion is the version of the JSON encoding of trees written by Encode.
+// Decode rejects encodings of other versions.
+const EncodingVersion = 1
+
+// The kinds of nodes in the JSON encoding.
+const (
+    KindLiteral = "literal"
+    KindUnary   = "unary"
+    KindBinary  = "binary"
+    KindIdent   = "ident"
+    KindAssign  = "assign"
+    KindCall    = "call"
+)
+
+var (
+    ErrInvalidEncoding = errors.New("error invalid tree encoding")
+)
+
+// encodedTree is the JSON document of a tree.
+type encodedTree struct {
+    Version int          `json:"version"`
+    Root    *encodedNode `json:"root"`
+}
+
+// encodedNode is the JSON object of a Node.
+// Unary operators have their operand as the only child, binary operators and assignments have two children,
+// and calls have their arguments as children.
+type encodedNode struct {
+    Kind     string         `json:"kind"`
+    Operator string         `json:"operator,omitempty"`
+    Name     string         `json:"name,omitempty"`
+    Value    *encodedValue  `json:"value,omitempty"`
+    Children []*encodedNode `json:"children,omitempty"`
+    Token    *encodedToken  `json:"token,omitempty"`
+}
+
+// encodedValue is the JSON object of a number.Number, the text is exact in its backend.
+type encodedValue struct {
+    Backend   string `json:"backend"`
+    Precision uint   `json:"precision,omitempty"`
+    Text      string `json:"text"`
+}
+
+// encodedToken is the JSON object of the token a Node was parsed from.
+type encodedToken struct {
+    Type    string      `json:"type"`
+    Literal string      `json:"literal"`
+    Span    encodedSpan `json:"span"`
+}
+
+// encodedSpan is the JSON object of the location of a token in the input.
+type encodedSpan struct {
+    Offset int `json:"offset"`
+    Line   int `json:"line"`
+    Column int `json:"column"`
+    Length int `json:"length"`
+}
+
+// Encode returns the JSON encoding of a tree, which Decode turns back into the same tree.
+//
+// The encoding is a document like {"version": 1, "root": {...}}, where every node has a kind of
+// "literal", "unary", "binary", "ident", "assign" or "call", its operator, name or value, its children and its token.
+// Values are encoded with their backend and their exact text, so a rational 1/3 stays 1/3.
+// Incomplete trees, like operators with a missing operand, or values of backends outside package number can't be encoded.
+func Encode(n Node) ([]byte, error) {
+    root, err := encodeNode(n)
+    if err != nil {
+        return nil, err
+    }
+    return json.Marshal(encodedTree{Version: EncodingVersion, Root: root})
+}
+
+// encodeNode returns the JSON object of a node and its children.
+func encodeNode(n Node) (*encodedNode, error) {
+    if n == nil {
+        return nil, fmt.Errorf("%w: missing node", ErrInvalidEncoding)
+    }
+
+    e := &encodedNode{}
+    if tok := n.Source(); tok != nil {
+        e.Token = &encodedToken{Type: tok.LexicalType, Literal: tok.Literal, Span: encodedSpan(tok.Span)}
+    }
+
+    switch n := n.(type) {
+    case *Literal:
+        value, err := encodeValue(n.Value)
+        if err != nil {
+            return nil, err
+        }
+        e.Kind, e.Value = KindLiteral, value
+    case *Identifier:
+        e.Kind, e.Name = KindIdent, n.Name
+    case *Assignment:
+        e.Kind, e.Operator = KindAssign, "="
+    case *Call:
+        e.Kind, e.Name = KindCall, n.Name
+    case *Unary:
+        e.Kind, e.Operator = KindUnary, n.Operator
+    case *Binary:
+        e.Kind, e.Operator = KindBinary, n.Operator
+    default:
+        return nil, fmt.Errorf("%w: node of unknown type %T", ErrInvalidEncoding, n)
+    }
+
+    for _, child := range Children(n) {
+        c, err := encodeNode(child)
+        if err != nil {
+            return nil, err
+        }
+        e.Children = append(e.Children, c)
+    }
+    return e, nil
+}
+
+// encodeValue returns the JSON object of a Number.
+func encodeValue(value number.Number) (*encodedValue, error) {
+    switch v := value.(type) {
+    case number.Float:
+        return &encodedValue{Backend: number.FLOAT, Text: strconv.FormatFloat(float64(v), 'g', -1, 64)}, nil
+    case number.Rat:
+        return &encodedValue{Backend: number.RATIONAL, Text: v.String()}, nil
+    case number.BigFloat:
+        f := v.BigFloat()
+        return &encodedValue{Backend: number.BIGFLOAT, Precision: f.Prec(), Text: f.Text('g', -1)}, nil
+    case number.Int:
+        return &encodedValue{Backend: number.INT, Text: v.String()}, nil
+    case number.Complex:
+        return &encodedValue{Backend: number.COMPLEX, Text: strconv.FormatComplex(complex128(v), 'g', -1, 128)}, nil
+    default:
+        return nil, fmt.Errorf("%w: value %v of unknown backend %T", ErrInvalidEncoding, value, value)
+    }
+}
+
+// Decode returns the tree of a JSON encoding written by Encode.
+// The encoding is validated, unknown fields, kinds, operators and backends, and nodes with the wrong number of children are errors.
+func Decode(data []byte) (Node, error) {
+    decoder := json.NewDecoder(bytes.NewReader(data))
+    decoder.DisallowUnknownFields()
+    var tree encodedTree
+    if err := decoder.Decode(&tree); err != nil {
+        return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+    }
+    if decoder.More() {
+        return nil, fmt.Errorf("%w: data after the tree", ErrInvalidEncoding)
+    }
+    if tree.Version != EncodingVersion {
+        return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidEncoding, tree.Version)
+    }
+    return decodeNode(tree.Root, "root")
+}
+
+// decodeNode returns the Node of a JSON object, the path locates the object in errors.
+func decodeNode(e *encodedNode, path string) (Node, error) {
+    if e == nil {
+        return nil, fmt.Errorf("%w: %s: missing node", ErrInvalidEncoding, path)
+    }
+
+    var tok *token.Token
+    if e.Token != nil {
+        tok = &token.Token{LexicalType: e.Token.Type, Literal: e.Token.Literal, Span: token.Span(e.Token.Span)}
+    }
+
+    children := make([]Node, len(e.Children))
+    for i, child := range e.Children {
+        c, err := decodeNode(child, fmt.Sprintf("%s.children[%d]", path, i))
+        if err != nil {
+            return nil, err
+        }
+        children[i] = c
+    }
+
+    // A node has exactly the fields of its kind, a count of -1 allows any number of children.
+    fields := func(operator bool, name bool, value bool, count int) error {
+        for _, field := range []struct {
+            name     string
+            set      bool
+            expected bool
+        }{
+            {name: "operator", set: e.Operator != "", expected: operator},
+            {name: "name", set: e.Name != "", expected: name},
+            {name: "value", set: e.Value != nil, expected: value},
+        } {
+            if field.set && !field.expected {
+                return fmt.Errorf("%w: %s: %s node with a %s", ErrInvalidEncoding, path, e.Kind, field.name)
+            }
+            if !field.set && field.expected {
+                return fmt.Errorf("%w: %s: %s node without a %s", ErrInvalidEncoding, path, e.Kind, field.name)
+            }
+        }
+        if count >= 0 && len(children) != count {
+            return fmt.Errorf("%w: %s: %s node with %d children, expected %d", ErrInvalidEncoding, path, e.Kind, len(children), count)
+        }
+        return nil
+    }
+
+    switch e.Kind {
+    case KindLiteral:
+        if err := fields(false, false, true, 0); err != nil {
+            return nil, err
+        }
+        value, err := decodeValue(e.Value)
+        if err != nil {
+            return nil, fmt.Errorf("%w: %s: %v", ErrInvalidEncoding, path, err)
+        }
+        return NewLiteral(tok, value), nil
+    case KindIdent:
+        if err := fields(false, true, false, 0); err != nil {
+            return nil, err
+        }
+        return NewIdentifier(tok, e.Name), nil
+    case KindAssign:
+        if err := fields(true, false, false, 2); err != nil {
+            return nil, err
+        }
+        target, ok := children[0].(*Identifier)
+        if !ok || e.Operator != "=" {
+            return nil, fmt.Errorf("%w: %s: assignment '%s' to %s", ErrInvalidEncoding, path, e.Operator, children[0])
+        }
+        return NewAssignment(tok, target, children[1]), nil
+    case KindCall:
+        if err := fields(false, true, false, -1); err != nil {
+            return nil, err
+        }
+        return NewCall(tok, e.Name, children), nil
+    case KindUnary:
+        if err := fields(true, false, false, 1); err != nil {
+            return nil, err
+        }
+        if !token.IsPrefixOperator(e.Operator) {
+            return nil, fmt.Errorf("%w: %s: unknown unary operator '%s'", ErrInvalidEncoding, path, e.Operator)
+        }
+        return NewUnary(tok, e.Operator, children[0]), nil
+    case KindBinary:
+        if err := fields(true, false, false, 2); err != nil {
+            return nil, err
+        }
+        if !token.IsInfixOperator(e.Operator) {
+            return nil, fmt.Errorf("%w: %s: unknown binary operator '%s'", ErrInvalidEncoding, path, e.Operator)
+        }
+        return NewBinary(tok, e.Operator, children[0], children[1]), nil
+    default:
+        return nil, fmt.Errorf("%w: %s: unknown kind '%s'", ErrInvalidEncoding, path, e.Kind)
+    }
+}
+
+// decodeValue returns the Number of a JSON object.
+func decodeValue(e *encodedValue) (number.Number, error) {
+    if e.Precision != 0 && e.Backend != number.BIGFLOAT {
+        return nil, fmt.Errorf("precision of a %s value", e.Backend)
+    }
+
+    switch e.Backend {
+    case number.FLOAT:
+        f, err := strconv.ParseFloat(e.Text, 64)
+        if err != nil {
+            return nil, fmt.Errorf("%w '%s'", number.ErrInvalidLiteral, e.Text)
+        }
+        return number.Float(f), nil
+    case number.RATIONAL:
+        r, ok := new(big.Rat).SetString(e.Text)
+        if !ok {
+            return nil, fmt.Errorf("%w '%s'", number.ErrInvalidLiteral, e.Text)
+        }
+        return number.NewRat(r), nil
+    case number.BIGFLOAT:
+        if e.Precision == 0 || e.Precision > big.MaxPrec {
+            return nil, fmt.Errorf("invalid precision %d", e.Precision)
+        }
+        f, _, err := big.ParseFloat(e.Text, 10, e.Precision, big.ToNearestEven)
+        if err != nil {
+            return nil, fmt.Errorf("%w '%s'", number.ErrInvalidLiteral, e.Text)
+        }
+        return number.NewBigFloat(f), nil
+    case number.INT:
+        i, err := strconv.ParseInt(e.Text, 10, 64)
+        if err != nil {
+            return nil, fmt.Errorf("%w '%s'", number.ErrInvalidLiteral, e.Text)
+        }
+        return number.Int(i), nil
+    case number.COMPLEX:
+        c, err := strconv.ParseComplex(e.Text, 128)
+        if err != nil {
+            return nil, fmt.Errorf("%w '%s'", number.ErrInvalidLiteral, e.Text)
+        }
+        return number.Complex(c), nil
+    default:
+        return nil, fmt.Errorf("%w: %s", number.ErrUnknownBackend, e.Backend)
+    }
+}
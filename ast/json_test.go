@@ -0,0 +1,157 @@
+package ast
+
+import (
+    "LexicalCalculator/number"
+    "LexicalCalculator/token"
+    "errors"
+    "math/big"
+    "reflect"
+    "testing"
+)
+
+func TestEncode(t *testing.T) {
+    tok := func(lexicalType string, literal string, offset int) *token.Token {
+        return &token.Token{LexicalType: lexicalType, Literal: literal, Span: token.Span{Offset: offset, Line: 1, Column: offset + 1, Length: len(literal)}}
+    }
+
+    // -2.50 ^ x
+    node := NewUnary(tok(token.MINUS, "-", 0), "-",
+        NewBinary(tok(token.CIRCUMFLEX, "^", 6), "^",
+            NewLiteral(tok(token.FLOAT, "2.50", 1), number.Float(2.5)),
+            NewIdentifier(tok(token.IDENT, "x", 8), "x"),
+        ),
+    )
+    data, err := Encode(node)
+    if err != nil {
+        t.Fatalf("Error encoding %s: got error %v.\n", node, err)
+    }
+
+    expected := `{"version":1,"root":{"kind":"unary","operator":"-","children":[` +
+        `{"kind":"binary","operator":"^","children":[` +
+        `{"kind":"literal","value":{"backend":"float","text":"2.5"},"token":{"type":"FLOAT","literal":"2.50","span":{"offset":1,"line":1,"column":2,"length":4}}},` +
+        `{"kind":"ident","name":"x","token":{"type":"IDENT","literal":"x","span":{"offset":8,"line":1,"column":9,"length":1}}}],` +
+        `"token":{"type":"^","literal":"^","span":{"offset":6,"line":1,"column":7,"length":1}}}],` +
+        `"token":{"type":"-","literal":"-","span":{"offset":0,"line":1,"column":1,"length":1}}}}`
+    if string(data) != expected {
+        t.Errorf("Error encoding %s: expected %s, got %s.\n", node, expected, data)
+    }
+
+    decoded, err := Decode(data)
+    if err != nil {
+        t.Fatalf("Error decoding %s: got error %v.\n", data, err)
+    }
+    if !reflect.DeepEqual(decoded, node) {
+        t.Errorf("Error decoding %s: expected %s, got %s.\n", data, node, decoded)
+    }
+    if _, ok := decoded.(*Unary); !ok {
+        t.Errorf("Error decoding %s: expected a prefix operator, got %T.\n", data, decoded)
+    }
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+    value := func(n number.Number) Node { return NewLiteral(nil, n) }
+    x := NewIdentifier(nil, "x")
+
+    testCases := []Node{
+        value(number.Float(0.1)),
+        value(number.Float(-1e-300)),
+        value(number.NewRat(big.NewRat(-1, 3))),
+        value(number.Int(-9223372036854775808)),
+        value(number.Complex(1 - 2i)),
+        NewAssignment(token.New(token.ASSIGN, "="), NewIdentifier(token.New(token.IDENT, "y"), "y"),
+            NewBinary(nil, "+", value(number.Float(1)), x)),
+        NewCall(token.New(token.IDENT, "max"), "max", []Node{x, NewUnary(nil, "-", x)}),
+        NewCall(nil, "f", make([]Node, 0)),
+    }
+
+    for _, node := range testCases {
+        data, err := Encode(node)
+        if err != nil {
+            t.Errorf("Error encoding %s: got error %v.\n", node, err)
+            continue
+        }
+        decoded, err := Decode(data)
+        if err != nil {
+            t.Errorf("Error decoding %s: got error %v.\n", data, err)
+            continue
+        }
+        if !reflect.DeepEqual(decoded, node) {
+            t.Errorf("Error decoding %s: expected %s, got %s.\n", data, node, decoded)
+        }
+    }
+}
+
+func TestEncode_BigFloat(t *testing.T) {
+    third, _ := number.NewBigFloatBackend(100).Convert(number.NewRat(big.NewRat(1, 3)))
+    data, err := Encode(NewLiteral(nil, third))
+    if err != nil {
+        t.Fatalf("Error encoding %s: got error %v.\n", third, err)
+    }
+
+    decoded, err := Decode(data)
+    if err != nil {
+        t.Fatalf("Error decoding %s: got error %v.\n", data, err)
+    }
+    literal, ok := decoded.(*Literal)
+    if !ok {
+        t.Fatalf("Error decoding %s: expected a literal, got %T.\n", data, decoded)
+    }
+    result, ok := literal.Value.(number.BigFloat)
+    if !ok || result.BigFloat().Prec() != 100 || result.BigFloat().Cmp(third.(number.BigFloat).BigFloat()) != 0 {
+        t.Errorf("Error decoding %s: expected %s with 100 bits of precision, got %s.\n", data, third, decoded)
+    }
+}
+
+func TestEncode_Error(t *testing.T) {
+    testCases := []Node{
+        nil,
+        NewBinary(nil, "+", NewIdentifier(nil, "x"), nil),
+        NewCall(nil, "max", []Node{nil}),
+    }
+
+    for _, node := range testCases {
+        if _, err := Encode(node); !errors.Is(err, ErrInvalidEncoding) {
+            t.Errorf("Error encoding %v: expected error %v, got %v.\n", node, ErrInvalidEncoding, err)
+        }
+    }
+}
+
+func TestDecode_Error(t *testing.T) {
+    testCases := []string{
+        ``,
+        `[]`,
+        `{"version":2,"root":{"kind":"ident","name":"x"}}`,
+        `{"version":1}`,
+        `{"version":1,"root":{"kind":"ident","name":"x"},"extra":1}`,
+        `{"version":1,"root":{"kind":"ident","name":"x"}} {}`,
+        `{"version":1,"root":{"kind":"tree"}}`,
+        `{"version":1,"root":{"kind":"ident"}}`,
+        `{"version":1,"root":{"kind":"ident","name":"x","operator":"+"}}`,
+        `{"version":1,"root":{"kind":"literal"}}`,
+        `{"version":1,"root":{"kind":"literal","value":{"backend":"decimal","text":"1"}}}`,
+        `{"version":1,"root":{"kind":"literal","value":{"backend":"int","text":"1.5"}}}`,
+        `{"version":1,"root":{"kind":"literal","value":{"backend":"bigfloat","text":"1"}}}`,
+        `{"version":1,"root":{"kind":"literal","value":{"backend":"float","precision":64,"text":"1"}}}`,
+        `{"version":1,"root":{"kind":"unary","operator":"-"}}`,
+        `{"version":1,"root":{"kind":"binary","operator":"+","children":[{"kind":"ident","name":"x"}]}}`,
+        `{"version":1,"root":{"kind":"binary","operator":"+","children":[{"kind":"ident","name":"x"},null]}}`,
+        `{"version":1,"root":{"kind":"assign","operator":"=","children":[{"kind":"literal","value":{"backend":"float","text":"1"}},{"kind":"ident","name":"x"}]}}`,
+        `{"version":1,"root":{"kind":"call","children":[{"kind":"ident","name":"x"}]}}`,
+        `{"version":1,"root":{"kind":"binary","operator":"??","children":[{"kind":"ident","name":"x"},{"kind":"ident","name":"y"}]}}`,
+        `{"version":1,"root":{"kind":"binary","operator":"~","children":[{"kind":"ident","name":"x"},{"kind":"ident","name":"y"}]}}`,
+        `{"version":1,"root":{"kind":"unary","operator":"*","children":[{"kind":"ident","name":"x"}]}}`,
+    }
+
+    for _, data := range testCases {
+        if _, err := Decode([]byte(data)); !errors.Is(err, ErrInvalidEncoding) {
+            t.Errorf("Error decoding %s: expected error %v, got %v.\n", data, ErrInvalidEncoding, err)
+        }
+    }
+
+    // Errors name the path of the invalid node.
+    data := `{"version":1,"root":{"kind":"binary","operator":"+","children":[{"kind":"ident","name":"x"},{"kind":"unary","operator":"!","children":[{"kind":"ident","name":"y"}]}]}}`
+    expected := "error invalid tree encoding: root.children[1]: unknown unary operator '!'"
+    if _, err := Decode([]byte(data)); err == nil || err.Error() != expected {
+        t.Errorf("Error decoding %s: expected error %s, got %v.\n", data, expected, err)
+    }
+}
@@ -0,0 +1,230 @@
+/*
+Package calculus differentiates ast trees symbolically.
+
+Derive applies the sum, product, quotient and power rules to the operators, and the chain rule to the built-in functions.
+The derivative is simplified by package optimizer, and by rules holding wherever the derivative is defined, like 'x / x' becoming 1.
+*/
+package calculus
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/number"
+    "errors"
+    "fmt"
+)
+
+var (
+    ErrNotDifferentiable = errors.New("error expression is not differentiable")
+)
+
+// Derive returns the derivative of a tree with respect to a variable.
+// Identifiers other than the variable are constants. Calls that depend on the variable must be calls of
+// differentiable built-in functions, the derivatives of floor, ceil, round, trunc, min and max aren't defined.
+// The numbers of the derivative, like the 2 in the derivative of 'x ^ 2', are of the backend of the tree.
+func Derive(n ast.Node, variable string) (ast.Node, error) {
+    d := &deriver{variable: variable, backend: backendOf(n)}
+    derivative, err := d.derive(n)
+    if err != nil {
+        return nil, err
+    }
+    return d.simplify(derivative), nil
+}
+
+// deriver builds the derivative of a tree.
+type deriver struct {
+    variable string
+    backend  number.Backend
+}
+
+// derive returns the derivative of a node.
+func (d *deriver) derive(n ast.Node) (ast.Node, error) {
+    if n == nil {
+        return nil, fmt.Errorf("%w: incomplete expression", ErrNotDifferentiable)
+    }
+    if !d.dependsOn(n) {
+        return d.constant(0), nil
+    }
+
+    switch n := n.(type) {
+    case *ast.Identifier:
+        // Identifiers other than the variable are constants, handled above.
+        return d.constant(1), nil
+    case *ast.Assignment:
+        return nil, fmt.Errorf("%w: assignment to '%s'", ErrNotDifferentiable, n.Target.Name)
+    case *ast.Call:
+        return d.deriveCall(n)
+    case *ast.Unary:
+        if n.Operator == "~" {
+            return nil, fmt.Errorf("%w: '~' is discontinuous", ErrNotDifferentiable)
+        }
+        du, err := d.derive(n.Operand)
+        if err != nil || n.Operator == "+" {
+            return du, err
+        }
+        return neg(du), nil
+    case *ast.Binary:
+        return d.deriveBinary(n)
+    }
+    return nil, fmt.Errorf("%w: unknown node %s", ErrNotDifferentiable, n)
+}
+
+// deriveBinary applies the sum, product, quotient and power rules.
+func (d *deriver) deriveBinary(n *ast.Binary) (ast.Node, error) {
+    u, v := n.Left, n.Right
+    du, err := d.derive(u)
+    if err != nil {
+        return nil, err
+    }
+    dv, err := d.derive(v)
+    if err != nil {
+        return nil, err
+    }
+
+    switch n.Operator {
+    case "+":
+        return add(du, dv), nil
+    case "-":
+        return sub(du, dv), nil
+    case "*":
+        // (u * v)' = u' * v + u * v'
+        return add(mul(du, v), mul(u, dv)), nil
+    case "/":
+        // (u / v)' = (u' * v - u * v') / v ^ 2
+        return quo(sub(mul(du, v), mul(u, dv)), pow(v, d.constant(2))), nil
+    case "^":
+        switch {
+        case !d.dependsOn(v):
+            // (u ^ c)' = c * u ^ (c - 1) * u'
+            return mul(mul(v, pow(u, sub(v, d.constant(1)))), du), nil
+        case !d.dependsOn(u):
+            // (c ^ v)' = c ^ v * ln(c) * v'
+            return mul(mul(n, call("ln", u)), dv), nil
+        default:
+            // (u ^ v)' = u ^ v * (v' * ln(u) + v * u' / u)
+            return mul(n, add(mul(dv, call("ln", u)), quo(mul(v, du), u))), nil
+        }
+    case "//", "%", "rem", "&", "|", "xor", "<<", ">>":
+        // Like floor(x), floored and truncated divisions jump at every multiple of the divisor, and bitwise operators at every integer.
+        return nil, fmt.Errorf("%w: '%s' is discontinuous", ErrNotDifferentiable, n.Operator)
+    }
+    return nil, fmt.Errorf("%w: unknown operator '%s'", ErrNotDifferentiable, n.Operator)
+}
+
+// deriveCall applies the chain rule to a call of a built-in function, f(u)' = f'(u) * u'.
+func (d *deriver) deriveCall(n *ast.Call) (ast.Node, error) {
+    if n.Name == "hypot" && len(n.Args) == 2 {
+        // hypot(u, v)' = (u * u' + v * v') / hypot(u, v)
+        u, v := n.Args[0], n.Args[1]
+        du, err := d.derive(u)
+        if err != nil {
+            return nil, err
+        }
+        dv, err := d.derive(v)
+        if err != nil {
+            return nil, err
+        }
+        return quo(add(mul(u, du), mul(v, dv)), n), nil
+    }
+
+    if len(n.Args) != 1 {
+        return nil, fmt.Errorf("%w: %s", ErrNotDifferentiable, n.Name)
+    }
+    u := n.Args[0]
+    du, err := d.derive(u)
+    if err != nil {
+        return nil, err
+    }
+
+    one, two := d.constant(1), d.constant(2)
+    var outer ast.Node
+    switch n.Name {
+    case "sqrt":
+        outer = quo(one, mul(two, n))
+    case "cbrt":
+        outer = quo(one, mul(d.constant(3), pow(n, two)))
+    case "exp":
+        outer = n
+    case "ln":
+        outer = quo(one, u)
+    case "log10":
+        outer = quo(one, mul(u, call("ln", d.constant(10))))
+    case "log2":
+        outer = quo(one, mul(u, call("ln", two)))
+    case "sin":
+        outer = call("cos", u)
+    case "cos":
+        outer = neg(call("sin", u))
+    case "tan":
+        outer = quo(one, pow(call("cos", u), two))
+    case "asin":
+        outer = quo(one, call("sqrt", sub(one, pow(u, two))))
+    case "acos":
+        outer = neg(quo(one, call("sqrt", sub(one, pow(u, two)))))
+    case "atan":
+        outer = quo(one, add(one, pow(u, two)))
+    case "sinh":
+        outer = call("cosh", u)
+    case "cosh":
+        outer = call("sinh", u)
+    case "tanh":
+        outer = quo(one, pow(call("cosh", u), two))
+    case "asinh":
+        outer = quo(one, call("sqrt", add(pow(u, two), one)))
+    case "acosh":
+        outer = quo(one, call("sqrt", sub(pow(u, two), one)))
+    case "atanh":
+        outer = quo(one, sub(one, pow(u, two)))
+    case "abs":
+        outer = quo(u, n)
+    default:
+        return nil, fmt.Errorf("%w: %s", ErrNotDifferentiable, n.Name)
+    }
+    return mul(outer, du), nil
+}
+
+// dependsOn checks whether a node depends on the variable.
+func (d *deriver) dependsOn(n ast.Node) bool {
+    if identifier, ok := n.(*ast.Identifier); ok {
+        return identifier.Name == d.variable
+    }
+    for _, child := range ast.Children(n) {
+        if d.dependsOn(child) {
+            return true
+        }
+    }
+    return false
+}
+
+// constant creates a literal of an integer in the backend of the tree.
+func (d *deriver) constant(i int64) ast.Node {
+    value, err := d.backend.Convert(number.Int(i))
+    if err != nil {
+        value = number.Int(i)
+    }
+    return ast.NewLiteral(nil, value)
+}
+
+// backendOf returns the backend of the first real value in a tree, number.FloatBackend if there is none.
+// Imaginary literals are complex in any backend, they don't tell the backend of the tree.
+func backendOf(n ast.Node) number.Backend {
+    if value := firstValue(n); value != nil {
+        return number.BackendOf(value)
+    }
+    return number.FloatBackend
+}
+
+// firstValue returns the first real value in a tree, or nil if there is none.
+func firstValue(n ast.Node) number.Number {
+    if literal, ok := n.(*ast.Literal); ok {
+        if _, ok := literal.Value.(number.Complex); ok {
+            return nil
+        }
+        return literal.Value
+    }
+    for _, child := range ast.Children(n) {
+        if value := firstValue(child); value != nil {
+            return value
+        }
+    }
+    return nil
+}
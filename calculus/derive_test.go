@@ -0,0 +1,133 @@
+package calculus
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/lexer"
+    "LexicalCalculator/number"
+    "LexicalCalculator/parser"
+    "LexicalCalculator/support"
+    "errors"
+    "math/big"
+    "testing"
+)
+
+// parse parses a bare equation, undefined variables are free variables.
+func parse(t *testing.T, equation string) ast.Node {
+    n, err := parser.New(lexer.New()).ParseEquation(equation)
+    if err != nil {
+        t.Fatalf("Error parsing %s: got error %v.\n", equation, err)
+    }
+    return n
+}
+
+func TestDerive(t *testing.T) {
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        {input: "5", result: "0.0000"},
+        {input: "y", result: "0.0000"},
+        {input: "x", result: "1.0000"},
+        {input: "-x", result: "-1.0000"},
+        {input: "y * x", result: "y"},
+        {input: "x ^ 2", result: "(* x 2.0000)"},
+        {input: "x ^ 3 + 2 * x + 1", result: "(+ (* (^ x 2.0000) 3.0000) 2.0000)"},
+        {input: "1 / x", result: "(/ -1.0000 (^ x 2.0000))"},
+        {input: "exp(x * 2)", result: "(* (exp (* x 2.0000)) 2.0000)"},
+        {input: "ln(y)", result: "0.0000"},
+        {input: "sin(x)", result: "(cos x)"},
+        // Simplifications.
+        {input: "x ^ 1", result: "1.0000"},
+        {input: "y * x ^ 1", result: "y"},
+        {input: "ln(x) / x", result: "(/ (- 1.0000 (ln x)) (^ x 2.0000))"},
+        {input: "x * ln(x)", result: "(+ (ln x) 1.0000)"},
+        {input: "x ^ x", result: "(* (+ (ln x) 1.0000) (^ x x))"},
+    }
+
+    for _, tc := range testCases {
+        d, err := Derive(parse(t, tc.input), "x")
+        if err != nil {
+            t.Errorf("Error deriving %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        if d.String() != tc.result {
+            t.Errorf("Error deriving %s: expected %s, got %s.\n", tc.input, tc.result, d)
+        }
+    }
+}
+
+func TestDerive_Numerical(t *testing.T) {
+    // The derivatives are compared with the central differences of the expressions.
+    inputs := []string{
+        "3 * x ^ 2 - 2 * x + 1",
+        "(x + 1) / (x - 4)",
+        "x ^ x",
+        "2 ^ x * y",
+        "x ^ y",
+        "sqrt(x ^ 2 + 1) + cbrt(x)",
+        "exp(-x) * ln(x) + log10(x) - log2(x)",
+        "sin(x) * cos(x) + tan(x / 2)",
+        "asin(x / 4) + acos(x / 4) + atan(x)",
+        "sinh(x) + cosh(x) - tanh(x)",
+        "asinh(x) + acosh(x + 1) + atanh(x / 4)",
+        "abs(x - 5) + hypot(x, y)",
+        "--x * +x",
+    }
+    const h = 1e-6
+
+    for _, input := range inputs {
+        n := parse(t, input)
+        d, err := Derive(n, "x")
+        if err != nil {
+            t.Errorf("Error deriving %s: got error %v.\n", input, err)
+            continue
+        }
+
+        for _, x := range []float64{0.5, 1.5, 2.5} {
+            at := func(n ast.Node, x float64) float64 {
+                env := ast.NewEnvironment()
+                env.Set("x", number.Float(x))
+                env.Set("y", number.Float(1.5))
+                re, err := ast.EvaluateIn(n, env)
+                if err != nil {
+                    t.Fatalf("Error evaluating %s at x = %f: got error %v.\n", n, x, err)
+                }
+                return re.Float64()
+            }
+            expected := (at(n, x+h) - at(n, x-h)) / (2 * h)
+            if re := at(d, x); !support.AlmostEqual(re, expected, 0.0001) {
+                t.Errorf("Error deriving %s at x = %f: expected %f, got %f from %s.\n", input, x, expected, re, d)
+            }
+        }
+    }
+}
+
+func TestDerive_Error(t *testing.T) {
+    for _, input := range []string{"floor(x)", "max(x, 1)", "x = 2", "x % 2", "7 // x", "x rem 2", "x & 1", "1 << x", "~x"} {
+        if _, err := Derive(parse(t, input), "x"); !errors.Is(err, ErrNotDifferentiable) {
+            t.Errorf("Error deriving %s: expected error %s, got %v.\n", input, ErrNotDifferentiable, err)
+        }
+    }
+
+    // Functions that don't depend on the variable are constants.
+    if d, err := Derive(parse(t, "floor(y) * x"), "x"); err != nil || d.String() != "(floor y)" {
+        t.Errorf("Error deriving floor(y) * x: expected (floor y), got %v, error %v.\n", d, err)
+    }
+}
+
+func TestDerive_Backend(t *testing.T) {
+    p := parser.New(lexer.New())
+    p.SetBackend(number.RatBackend)
+    n, _ := p.ParseEquation("x ^ 3 / 3")
+
+    d, err := Derive(n, "x")
+    if err != nil {
+        t.Fatalf("Error deriving x ^ 3 / 3: got error %v.\n", err)
+    }
+    env := ast.NewEnvironment()
+    env.Set("x", number.NewRat(big.NewRat(1, 3)))
+    re, err := ast.EvaluateIn(d, env)
+    if err != nil || re.String() != "1/9" {
+        t.Errorf("Error evaluating the derivative of x ^ 3 / 3 at 1/3: expected 1/9, got %v, error %v.\n", re, err)
+    }
+}
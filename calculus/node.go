@@ -0,0 +1,111 @@
+package calculus
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/number"
+    "LexicalCalculator/token"
+)
+
+// The builders below create the nodes of a derivative.
+// They drop the terms that are zero by the rules of the derivative, like the derivatives of constants,
+// so the derivative doesn't grow with terms like '0 * x'. Further simplifications are left to package optimizer.
+
+// add returns u + v.
+func add(u ast.Node, v ast.Node) ast.Node {
+    switch {
+    case isZero(u):
+        return v
+    case isZero(v):
+        return u
+    }
+    return operator(token.PLUS, u, v)
+}
+
+// sub returns u - v.
+func sub(u ast.Node, v ast.Node) ast.Node {
+    switch {
+    case isZero(v):
+        return u
+    case isZero(u):
+        return neg(v)
+    }
+    return operator(token.MINUS, u, v)
+}
+
+// mul returns u * v.
+func mul(u ast.Node, v ast.Node) ast.Node {
+    switch {
+    case isZero(u), isZero(v):
+        return zeroOf(u, v)
+    case isOne(u):
+        return v
+    case isOne(v):
+        return u
+    }
+    return operator(token.ASTERISK, u, v)
+}
+
+// quo returns u / v.
+func quo(u ast.Node, v ast.Node) ast.Node {
+    switch {
+    case isZero(u):
+        return u
+    case isOne(v):
+        return u
+    }
+    return operator(token.SLASH, u, v)
+}
+
+// pow returns u ^ v.
+func pow(u ast.Node, v ast.Node) ast.Node {
+    if isOne(v) {
+        return u
+    }
+    return operator(token.CIRCUMFLEX, u, v)
+}
+
+// neg returns -u.
+func neg(u ast.Node) ast.Node {
+    switch {
+    case isZero(u):
+        return u
+    }
+    if unary, ok := u.(*ast.Unary); ok && unary.Operator == token.MINUS {
+        return unary.Operand
+    }
+    return ast.NewUnary(nil, token.MINUS, u)
+}
+
+// call returns a call of a function with one argument.
+func call(name string, u ast.Node) ast.Node {
+    return ast.NewCall(nil, name, []ast.Node{u})
+}
+
+// operator returns a binary operator node.
+func operator(op string, u ast.Node, v ast.Node) ast.Node {
+    return ast.NewBinary(nil, op, u, v)
+}
+
+// isZero checks whether a node is the value 0.
+func isZero(n ast.Node) bool {
+    literal, ok := n.(*ast.Literal)
+    return ok && literal.Value.IsZero()
+}
+
+// isOne checks whether a node is the value 1.
+func isOne(n ast.Node) bool {
+    literal, ok := n.(*ast.Literal)
+    if !ok {
+        return false
+    }
+    cmp, err := literal.Value.Cmp(number.Int(1))
+    return err == nil && cmp == 0
+}
+
+// zeroOf returns the operand that is the value 0.
+func zeroOf(u ast.Node, v ast.Node) ast.Node {
+    if isZero(u) {
+        return u
+    }
+    return v
+}
@@ -0,0 +1,71 @@
+package calculus
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/optimizer"
+)
+
+// The rules below simplify derivatives beyond package optimizer, which keeps the result of a tree for every value of its variables.
+// They hold wherever the derivative is defined, like 'x / x' being 1 except at 0, where the derivative itself is usually undefined,
+// so they aren't optimizer identities.
+
+// simplify optimizes a derivative and applies the rules of derivatives until the tree stops changing.
+// Rules can create constants for the optimizer to fold, like '2 * x ^ 0' becoming '2 * 1'.
+func (d *deriver) simplify(n ast.Node) ast.Node {
+    n = optimizer.Optimize(n, nil)
+    for {
+        simplified := optimizer.Optimize(d.rewrite(n), nil)
+        if simplified.String() == n.String() {
+            return simplified
+        }
+        n = simplified
+    }
+}
+
+// rewrite applies the rules to a node after its children:
+// 'a ^ 0' becomes 1, 'a ^ 1' becomes 'a', and 'a / a', '(1 / a) * a' and 'a * (1 / a)' become 1.
+func (d *deriver) rewrite(n ast.Node) ast.Node {
+    switch n := n.(type) {
+    case *ast.Unary:
+        return ast.NewUnary(n.Token, n.Operator, d.rewrite(n.Operand))
+    case *ast.Call:
+        args := make([]ast.Node, len(n.Args))
+        for i, arg := range n.Args {
+            args[i] = d.rewrite(arg)
+        }
+        return ast.NewCall(n.Token, n.Name, args)
+    case *ast.Binary:
+        left, right := d.rewrite(n.Left), d.rewrite(n.Right)
+        switch n.Operator {
+        case "^":
+            if isZero(right) {
+                return d.constant(1)
+            }
+            if isOne(right) {
+                return left
+            }
+        case "/":
+            if equal(left, right) {
+                return d.constant(1)
+            }
+        case "*":
+            if isReciprocalOf(left, right) || isReciprocalOf(right, left) {
+                return d.constant(1)
+            }
+        }
+        return ast.NewBinary(n.Token, n.Operator, left, right)
+    default:
+        return n
+    }
+}
+
+// isReciprocalOf checks whether a node is '1 / a' of the other node a.
+func isReciprocalOf(n ast.Node, a ast.Node) bool {
+    quotient, ok := n.(*ast.Binary)
+    return ok && quotient.Operator == "/" && isOne(quotient.Left) && equal(quotient.Right, a)
+}
+
+// equal checks whether two nodes are the same tree.
+func equal(a ast.Node, b ast.Node) bool {
+    return a.String() == b.String()
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+    "LexicalCalculator/format"
+    "LexicalCalculator/number"
+    "LexicalCalculator/parser"
+    "encoding/json"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "math"
+    "math/cmplx"
+    "strings"
+)
+
+// The exit codes of the command line.
+const (
+    exitOK    = 0
+    exitError = 1 // The equation is invalid, or its result can't be printed in the format.
+    exitUsage = 2 // The flags are invalid, like the exit code of the flag package.
+)
+
+// options are the flags of the command line.
+type options struct {
+    equation    string
+    hasEquation bool // Whether -e is given, the equation can be empty.
+    format      format.Format
+    json        bool
+}
+
+// parseFlags parses the flags of the command line, like `-e '1 + 2 * 3' --format scientific --precision 3`.
+// --format and --precision also set the format of the interactive calculator, the other flags need an equation.
+// Invalid flags are reported to stderr along with the usage.
+func parseFlags(args []string, stderr io.Writer) (options, error) {
+    flags := flag.NewFlagSet("calculator", flag.ContinueOnError)
+    flags.SetOutput(stderr)
+    equation := flags.String("e", "", "evaluate the `equation`, print its result and exit")
+    notation := flags.String("format", format.DEFAULT, "print results in the `notation`: default, auto, fixed, significant, scientific, engineering, hex, binary, octal or fraction")
+    precision := flags.Int("precision", -1, "print results with the `digits`, decimal places or significant digits depending on the format")
+    asJSON := flags.Bool("json", false, "print the result or the errors of the equation as JSON")
+    if err := flags.Parse(args); err != nil {
+        return options{}, err
+    }
+    o, err := checkFlags(flags, *equation, *notation, *precision, *asJSON)
+    if err != nil {
+        fmt.Fprintf(stderr, "calculator: %s\n", err)
+        flags.Usage()
+    }
+    return o, err
+}
+
+// checkFlags checks the parsed flags and combines the format flags into a format.
+func checkFlags(flags *flag.FlagSet, equation, notation string, precision int, asJSON bool) (options, error) {
+    var o options
+    var hasPrecision bool
+    flags.Visit(func(f *flag.Flag) {
+        switch f.Name {
+        case "e":
+            o.hasEquation = true
+        case "precision":
+            hasPrecision = true
+        }
+    })
+    if flags.NArg() > 0 {
+        return options{}, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+    }
+    if asJSON && !o.hasEquation {
+        return options{}, errors.New("--json needs an equation to evaluate with -e")
+    }
+    if hasPrecision && precision < 0 || precision > format.MaxDigits {
+        return options{}, fmt.Errorf("%w %d", format.ErrInvalidDigits, precision)
+    }
+
+    f, err := format.New(strings.ToLower(notation), precision)
+    if err != nil {
+        return options{}, err
+    }
+    if (f.Notation == "" || f.Notation == format.DEFAULT) && precision >= 0 {
+        f = f.WithPrecision(precision)
+    }
+    o.equation, o.format, o.json = equation, f, asJSON
+    return o, nil
+}
+
+// evaluate prints the result of an equation to stdout and returns exitOK,
+// or prints its errors to stderr and returns exitError, like `calculator -e '1 + 2 * 3'`.
+// Infinite and NaN results, like the overflow of `2 ^ 1024`, are errors so that scripts can tell them apart.
+func evaluate(p *parser.Parser, o options, stdout, stderr io.Writer) int {
+    result, err := p.EvaluateEquation(o.equation)
+    if err == nil {
+        err = checkFinite(result)
+    }
+    var text string
+    if err == nil {
+        text, err = o.format.Text(result)
+    }
+
+    if o.json {
+        out := jsonOutput{Equation: o.equation, Result: text}
+        w := stdout
+        if err != nil {
+            out = jsonOutput{Equation: o.equation, Errors: jsonErrors(err)}
+            w = stderr
+        }
+        encoded, _ := json.Marshal(out)
+        fmt.Fprintf(w, "%s\n", encoded)
+    } else if err == nil {
+        fmt.Fprintln(stdout, text)
+    } else {
+        printEquationError(stderr, o.equation, err)
+    }
+
+    if err != nil {
+        return exitError
+    }
+    return exitOK
+}
+
+// checkFinite checks that a result is a finite number, float64 and complex results can be infinite or NaN.
+func checkFinite(n number.Number) error {
+    switch n := n.(type) {
+    case number.Float:
+        if math.IsNaN(float64(n)) {
+            return fmt.Errorf("%w: %v", number.ErrNotANumber, n)
+        }
+        if math.IsInf(float64(n), 0) {
+            return fmt.Errorf("%w: %v", number.ErrInfinite, n)
+        }
+    case number.Complex:
+        if cmplx.IsNaN(complex128(n)) {
+            return fmt.Errorf("%w: %v", number.ErrNotANumber, n)
+        }
+        if cmplx.IsInf(complex128(n)) {
+            return fmt.Errorf("%w: %v", number.ErrInfinite, n)
+        }
+    }
+    return nil
+}
+
+// printEquationError prints the errors of an equation, every error found in the equation marked with carets under the offending token.
+func printEquationError(w io.Writer, equation string, err error) {
+    var errList parser.ErrorList
+    if !errors.As(err, &errList) {
+        fmt.Fprintf(w, "calculator: %s\n", err)
+        return
+    }
+    prefix := "calculator: "
+    fmt.Fprintf(w, "%s%s\n", prefix, equation)
+    for _, parseErr := range errList {
+        fmt.Fprintf(w, "%s %s\n", caret(prefix, parseErr.Span), errorMessage(parseErr))
+    }
+}
+
+// jsonOutput is the JSON printed by --json, either the result or the errors of the equation.
+type jsonOutput struct {
+    Equation string      `json:"equation"`
+    Result   string      `json:"result,omitempty"`
+    Errors   []jsonError `json:"errors,omitempty"`
+}
+
+// jsonError is an error of the equation, located by its span if it has one.
+type jsonError struct {
+    Message string `json:"message"`
+    Line    int    `json:"line,omitempty"`
+    Column  int    `json:"column,omitempty"`
+    Length  int    `json:"length,omitempty"`
+}
+
+// jsonErrors returns the errors of an equation as JSON errors.
+func jsonErrors(err error) []jsonError {
+    var errList parser.ErrorList
+    if !errors.As(err, &errList) {
+        return []jsonError{{Message: err.Error()}}
+    }
+    errs := make([]jsonError, len(errList))
+    for n, parseErr := range errList {
+        errs[n] = jsonError{Message: errorMessage(parseErr), Line: parseErr.Span.Line, Column: parseErr.Span.Column, Length: parseErr.Span.Length}
+    }
+    return errs
+}
+
+// errorMessage returns the message of a parser error without its position, or the message of the wrapped error if it has none.
+func errorMessage(err *parser.Error) string {
+    if err.Message == "" {
+        return err.Err.Error()
+    }
+    return err.Message
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+    "LexicalCalculator/lexer"
+    "LexicalCalculator/parser"
+    "bytes"
+    "io"
+    "testing"
+)
+
+func TestEvaluate(t *testing.T) {
+    testCases := []struct {
+        args   []string
+        stdout string
+        stderr string
+        code   int
+    }{
+        {args: []string{"-e", "1 + 2 * 3"}, stdout: "7.0000\n", code: exitOK},
+        {args: []string{"-e", "2 / 3", "--precision", "3"}, stdout: "0.667\n", code: exitOK},
+        {args: []string{"-e", "4 / 2", "--precision", "3"}, stdout: "2\n", code: exitOK},
+        {args: []string{"--format", "hex", "-e", "0b1010 << 4"}, stdout: "0xa0\n", code: exitOK},
+        {args: []string{"-e", "1 / 3", "--format", "SCIENTIFIC", "--precision", "4"}, stdout: "3.333e-01\n", code: exitOK},
+        {args: []string{"-e", "2 ^ 10", "--json"}, stdout: `{"equation":"2 ^ 10","result":"1024.0000"}` + "\n", code: exitOK},
+        {args: []string{"-e", "1 + * 2"}, stderr: "calculator: 1 + * 2\n                ^ missing operand before '*'\n", code: exitError},
+        {args: []string{"-e", "1 / 0"}, stderr: "calculator: error cannot use 0 as denominator\n", code: exitError},
+        {args: []string{"-e", "2 ^ 1024"}, stderr: "calculator: error number is infinite: +Inf\n", code: exitError},
+        {args: []string{"-e", "-2 ^ 1024 * 0"}, stderr: "calculator: error result is not a number: NaN\n", code: exitError},
+        {args: []string{"-e", "2 ^ 1024", "--json"}, stderr: `{"equation":"2 ^ 1024","errors":[{"message":"error number is infinite: +Inf"}]}` + "\n", code: exitError},
+        {args: []string{"-e", "2.5", "--format", "hex"}, stderr: "calculator: error number is not an integer: hex notation of 2.5\n", code: exitError},
+        {
+            args:   []string{"-e", "1 + * 2", "--json"},
+            stderr: `{"equation":"1 + * 2","errors":[{"message":"missing operand before '*'","line":1,"column":5,"length":1}]}` + "\n",
+            code:   exitError,
+        },
+        {args: []string{"-e", "1 / 0", "--json"}, stderr: `{"equation":"1 / 0","errors":[{"message":"error cannot use 0 as denominator"}]}` + "\n", code: exitError},
+    }
+
+    for _, tc := range testCases {
+        o, err := parseFlags(tc.args, io.Discard)
+        if err != nil {
+            t.Errorf("Error parsing flags %q: got error %v.\n", tc.args, err)
+            continue
+        }
+        var stdout, stderr bytes.Buffer
+        code := evaluate(parser.New(lexer.New()), o, &stdout, &stderr)
+        if code != tc.code || stdout.String() != tc.stdout || stderr.String() != tc.stderr {
+            t.Errorf("Error evaluating %q: expected %d, %q, %q, got %d, %q, %q.\n", tc.args, tc.code, tc.stdout, tc.stderr, code, stdout.String(), stderr.String())
+        }
+    }
+}
+
+func TestParseFlags_Error(t *testing.T) {
+    testCases := [][]string{
+        {"--bogus"},
+        {"--json"},
+        {"-e", "1", "2"},
+        {"-e", "1", "--format", "roman"},
+        {"-e", "1", "--precision", "-3"},
+        {"-e", "1", "--precision", "1001"},
+    }
+
+    for _, args := range testCases {
+        var stderr bytes.Buffer
+        if _, err := parseFlags(args, &stderr); err == nil || stderr.Len() == 0 {
+            t.Errorf("Error parsing flags %q: expected an error on stderr, got error %v, stderr %q.\n", args, err, stderr.String())
+        }
+    }
+}
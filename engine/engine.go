@@ -0,0 +1,195 @@
+/*
+Package engine embeds the calculator in Go programs.
+
+An Engine wires a lexer and a parser together, and evaluates bare equations like '5 + 2 * 3'.
+Host programs can register their own functions, constants and variables:
+
+    e := engine.New()
+    e.RegisterFunction("double", 1, func(args ...float64) (float64, error) { return 2 * args[0], nil })
+    e.SetConstant("g", 9.81)
+    result, err := e.Evaluate("double(g)")
+
+Results are numbers, Text renders them in the format of the Engine, like hexadecimal or engineering notation:
+
+    e := engine.New(engine.WithFormat(format.Format{Notation: format.ENGINEERING, Digits: 3}))
+    result, _ := e.Evaluate("4.7 / 100000")
+    text, err := e.Text(result) // text: 47.0µ
+
+Every Engine has its own functions, constants, variables and stored result, engines in the same process don't share any state.
+An Engine isn't safe for concurrent use.
+*/
+package engine
+
+import (
+    "LexicalCalculator/format"
+    "LexicalCalculator/function"
+    "LexicalCalculator/lexer"
+    "LexicalCalculator/number"
+    "LexicalCalculator/parser"
+    "errors"
+    "fmt"
+)
+
+var (
+    ErrInvalidName = errors.New("error invalid name")
+)
+
+// Func is a Go function that equations can call.
+// The arguments are converted to float64, the result is converted back to the backend of the Engine.
+type Func func(args ...float64) (float64, error)
+
+// Engine evaluates equations with its own functions, constants and variables.
+type Engine struct {
+    p      *parser.Parser
+    format format.Format
+}
+
+// Option configures an Engine.
+type Option func(e *Engine)
+
+// WithBackend sets the numeric backend the Engine evaluates equations with, number.FloatBackend by default.
+func WithBackend(backend number.Backend) Option {
+    return func(e *Engine) {
+        e.p.SetBackend(backend)
+    }
+}
+
+// WithFormat sets the format the Engine renders results in, the default notation by default.
+func WithFormat(f format.Format) Option {
+    return func(e *Engine) {
+        e.format = f
+    }
+}
+
+// New creates a new Engine.
+func New(options ...Option) *Engine {
+    e := &Engine{p: parser.New(lexer.New())}
+    for _, option := range options {
+        option(e)
+    }
+    return e
+}
+
+// Evaluate calculates the result of an equation like '5 + 2 * 3' or 'rate = 0.07'.
+// If the equation is invalid, the returned error is a parser.ErrorList holding every error found in the equation,
+// located by their spans in the equation.
+func (e *Engine) Evaluate(equation string) (number.Number, error) {
+    return e.p.EvaluateEquation(equation)
+}
+
+// Backend returns the numeric backend the Engine evaluates equations with.
+func (e *Engine) Backend() number.Backend {
+    return e.p.Backend()
+}
+
+// SetBackend sets the numeric backend the Engine evaluates equations with.
+// Variables, constants and the stored result are converted to the backend.
+func (e *Engine) SetBackend(backend number.Backend) {
+    e.p.SetBackend(backend)
+}
+
+// Format returns the format the Engine renders results in.
+func (e *Engine) Format() format.Format {
+    return e.format
+}
+
+// SetFormat sets the format the Engine renders results in.
+func (e *Engine) SetFormat(f format.Format) {
+    e.format = f
+}
+
+// Text renders a result in the format of the Engine.
+// Formats that need integers or real numbers, like format.HEX or format.FRACTION, fail on other results.
+func (e *Engine) Text(n number.Number) (string, error) {
+    return e.format.Text(n)
+}
+
+// RegisterFunction registers a Go function taking a fixed number of arguments, or any number if the arity is function.Variadic.
+// The function takes precedence over a built-in function of the same name.
+func (e *Engine) RegisterFunction(name string, arity int, fn Func) error {
+    if err := checkName(name); err != nil {
+        return err
+    }
+    if arity < function.Variadic {
+        return fmt.Errorf("%w: arity of '%s' is %d", function.ErrArity, name, arity)
+    }
+
+    minArgs, maxArgs := arity, arity
+    if arity == function.Variadic {
+        minArgs = 0
+    }
+    e.p.Environment().SetFunction(&function.Function{
+        Name:    name,
+        MinArgs: minArgs,
+        MaxArgs: maxArgs,
+        Call: func(args []number.Number) (number.Number, error) {
+            floats := make([]float64, len(args))
+            for i, arg := range args {
+                f, err := number.FloatBackend.Convert(arg)
+                if err != nil {
+                    return nil, err
+                }
+                floats[i] = f.Float64()
+            }
+            result, err := fn(floats...)
+            if err != nil {
+                return nil, err
+            }
+            return e.p.Backend().Convert(number.Float(result))
+        },
+        Real: func(args []float64) (float64, error) {
+            return fn(args...)
+        },
+    })
+    return nil
+}
+
+// SetConstant defines a constant, which equations can use but can't assign to.
+func (e *Engine) SetConstant(name string, value float64) error {
+    if err := checkName(name); err != nil {
+        return err
+    }
+    converted, err := e.p.Backend().Convert(number.Float(value))
+    if err != nil {
+        return err
+    }
+    e.p.Environment().SetConstant(name, converted)
+    return nil
+}
+
+// SetVariable defines a variable, or replaces its value if it's already defined.
+func (e *Engine) SetVariable(name string, value float64) error {
+    if err := checkName(name); err != nil {
+        return err
+    }
+    if e.p.Environment().IsConstant(name) {
+        return fmt.Errorf("%w: '%s' is a constant", ErrInvalidName, name)
+    }
+    converted, err := e.p.Backend().Convert(number.Float(value))
+    if err != nil {
+        return err
+    }
+    e.p.Environment().Set(name, converted)
+    return nil
+}
+
+// Variable returns the value of a variable or a constant, and whether it's defined.
+func (e *Engine) Variable(name string) (number.Number, bool) {
+    return e.p.Environment().Get(name)
+}
+
+// checkName checks whether a name can be used in equations.
+// Names start with a letter or an underscore followed by letters, digits and underscores, and aren't keywords.
+func checkName(name string) error {
+    if name == "" || lexer.IsKeyword(name) {
+        return fmt.Errorf("%w '%s'", ErrInvalidName, name)
+    }
+    for i, ch := range name {
+        letter := ch == '_' || ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z')
+        digit := '0' <= ch && ch <= '9'
+        if !letter && (!digit || i == 0) {
+            return fmt.Errorf("%w '%s'", ErrInvalidName, name)
+        }
+    }
+    return nil
+}
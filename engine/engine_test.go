@@ -0,0 +1,183 @@
+package engine
+
+import (
+    "LexicalCalculator/format"
+    "LexicalCalculator/function"
+    "LexicalCalculator/number"
+    "LexicalCalculator/parser"
+    "errors"
+    "fmt"
+    "math"
+    "testing"
+)
+
+func TestEngine_Evaluate(t *testing.T) {
+    e := New()
+    if err := e.RegisterFunction("double", 1, func(args ...float64) (float64, error) { return 2 * args[0], nil }); err != nil {
+        t.Fatalf("Error registering double: got error %v.\n", err)
+    }
+    if err := e.RegisterFunction("sum", function.Variadic, func(args ...float64) (float64, error) {
+        total := 0.0
+        for _, arg := range args {
+            total += arg
+        }
+        return total, nil
+    }); err != nil {
+        t.Fatalf("Error registering sum: got error %v.\n", err)
+    }
+    if err := e.SetConstant("g", 9.81); err != nil {
+        t.Fatalf("Error setting constant g: got error %v.\n", err)
+    }
+    if err := e.SetVariable("mass", 2); err != nil {
+        t.Fatalf("Error setting variable mass: got error %v.\n", err)
+    }
+
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        {input: "5 + 2 * 3", result: "11.0000"},
+        {input: "double(g)", result: "19.6200"},
+        {input: "force = mass * g", result: "19.6200"},
+        {input: "sum() + sum(1, 2, 3) + sqrt(force / g * 2)", result: "8.0000"},
+        {input: "ans - 1", result: "7.0000"},
+    }
+
+    for _, tc := range testCases {
+        val, err := e.Evaluate(tc.input)
+        if err != nil {
+            t.Errorf("Error evaluating %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        if fmt.Sprintf("%.4f", val) != tc.result {
+            t.Errorf("Error evaluating %s: expected %s, got %.4f.\n", tc.input, tc.result, val)
+        }
+    }
+
+    if force, ok := e.Variable("force"); !ok || math.Abs(force.Float64()-19.62) > 1e-9 {
+        t.Errorf("Error getting variable force: expected 19.62, got %v.\n", force)
+    }
+}
+
+func TestEngine_Evaluate_Error(t *testing.T) {
+    e := New()
+    e.SetConstant("g", 9.81)
+    e.RegisterFunction("fail", 0, func(args ...float64) (float64, error) { return 0, errors.New("error failed") })
+
+    testCases := []struct {
+        input   string
+        message string
+        column  int
+    }{
+        {input: "1 +", message: "missing operand after '+'", column: 4},
+        {input: "g = 2", message: "cannot assign to constant 'g'", column: 1},
+        {input: "double(2)", message: "unknown function 'double'", column: 1},
+        {input: "fail(1)", message: "fail takes 0 arguments, got 1", column: 1},
+    }
+
+    for _, tc := range testCases {
+        _, err := e.Evaluate(tc.input)
+        var errList parser.ErrorList
+        if !errors.As(err, &errList) {
+            t.Errorf("Error evaluating %s: expected an ErrorList, got %v.\n", tc.input, err)
+            continue
+        }
+        if errList[0].Message != tc.message || errList[0].Span.Column != tc.column {
+            t.Errorf("Error evaluating %s: expected %q at column %d, got %q at column %d.\n", tc.input, tc.message, tc.column, errList[0].Message, errList[0].Span.Column)
+        }
+    }
+
+    // Errors of Go functions are returned as they are.
+    if _, err := e.Evaluate("fail()"); err == nil || err.Error() != "error failed" {
+        t.Errorf("Error evaluating fail(): expected error failed, got %v.\n", err)
+    }
+
+    for _, name := range []string{"", "calc", "ans", "2x", "a-b"} {
+        if err := e.SetVariable(name, 1); !errors.Is(err, ErrInvalidName) {
+            t.Errorf("Error setting variable %q: expected error %s, got %v.\n", name, ErrInvalidName, err)
+        }
+    }
+    if err := e.SetVariable("g", 1); !errors.Is(err, ErrInvalidName) {
+        t.Errorf("Error setting constant g as a variable: expected error %s, got %v.\n", ErrInvalidName, err)
+    }
+    if err := e.RegisterFunction("f", -2, nil); !errors.Is(err, function.ErrArity) {
+        t.Errorf("Error registering f with arity -2: expected error %s, got %v.\n", function.ErrArity, err)
+    }
+}
+
+func TestEngine_Isolation(t *testing.T) {
+    a := New()
+    b := New(WithBackend(number.RatBackend))
+
+    a.SetVariable("x", 1)
+    a.RegisterFunction("f", 1, func(args ...float64) (float64, error) { return args[0], nil })
+    // Registering a function shadows the built-in function in this engine only.
+    a.RegisterFunction("sqrt", 1, func(args ...float64) (float64, error) { return -1, nil })
+
+    if _, err := b.Evaluate("x"); !errors.Is(err, parser.ErrEquation) {
+        t.Errorf("Error evaluating x in another engine: expected error %s, got %v.\n", parser.ErrEquation, err)
+    }
+    if _, err := b.Evaluate("f(1)"); !errors.Is(err, parser.ErrEquation) {
+        t.Errorf("Error evaluating f(1) in another engine: expected error %s, got %v.\n", parser.ErrEquation, err)
+    }
+    if val, err := a.Evaluate("sqrt(4)"); err != nil || val.Float64() != -1 {
+        t.Errorf("Error evaluating the registered sqrt(4): expected -1, got %v, error %v.\n", val, err)
+    }
+    if val, err := b.Evaluate("sqrt(4) + 1 / 3"); err != nil || val.String() != "7/3" {
+        t.Errorf("Error evaluating the built-in sqrt(4) + 1 / 3: expected 7/3, got %v, error %v.\n", val, err)
+    }
+}
+
+func TestEngine_Text(t *testing.T) {
+    e := New(WithBackend(number.RatBackend), WithFormat(format.Format{Notation: format.ENGINEERING, Digits: 3}))
+
+    testCases := []struct {
+        input  string
+        format format.Format
+        result string
+        err    error
+    }{
+        {input: "4.7 / 100000", format: e.Format(), result: "47.0µ"},
+        {input: "1 / 3", format: format.Format{Notation: format.FRACTION}, result: "1/3"},
+        {input: "1 / 3", format: format.Format{Notation: format.FIXED, Digits: 2}, result: "0.33"},
+        {input: "255", format: format.Format{Notation: format.HEX}, result: "0xff"},
+        {input: "2 ^ 20", format: format.Format{Separator: ","}, result: "1,048,576"},
+        {input: "1 / 8", format: format.Format{Notation: format.FIXED, Digits: 2}, result: "0.12"},
+        {input: "1 / 8", format: format.Format{Notation: format.FIXED, Digits: 2, Rounding: format.HALF_UP}, result: "0.13"},
+        {input: "-1 / 3", format: format.Format{}.WithPrecision(3), result: "-1/3"},
+        {input: "0.5", format: format.Format{Notation: format.BINARY}, err: number.ErrNotAnInteger},
+    }
+
+    for _, tc := range testCases {
+        e.SetFormat(tc.format)
+        val, err := e.Evaluate(tc.input)
+        if err != nil {
+            t.Errorf("Error evaluating %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        text, err := e.Text(val)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error formatting %s as %s: expected error %v, got %v.\n", tc.input, tc.format, tc.err, err)
+            continue
+        }
+        if text != tc.result {
+            t.Errorf("Error formatting %s as %s: expected %s, got %s.\n", tc.input, tc.format, tc.result, text)
+        }
+    }
+}
+
+func TestEngine_Keywords(t *testing.T) {
+    // Keywords are never lexed as identifiers, so names like them couldn't be used in equations.
+    for _, name := range []string{"calc", "ans", "mod", "rem", "xor"} {
+        e := New()
+        if err := e.SetConstant(name, 1); !errors.Is(err, ErrInvalidName) {
+            t.Errorf("Error setting constant %q: expected error %s, got %v.\n", name, ErrInvalidName, err)
+        }
+        if err := e.SetVariable(name, 1); !errors.Is(err, ErrInvalidName) {
+            t.Errorf("Error setting variable %q: expected error %s, got %v.\n", name, ErrInvalidName, err)
+        }
+        if err := e.RegisterFunction(name, 1, func(args ...float64) (float64, error) { return args[0], nil }); !errors.Is(err, ErrInvalidName) {
+            t.Errorf("Error registering function %q: expected error %s, got %v.\n", name, ErrInvalidName, err)
+        }
+    }
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/optimizer"
+    "LexicalCalculator/vm"
+    "sort"
+    "sync"
+)
+
+var (
+    ErrNotCompilable = vm.ErrNotCompilable
+)
+
+// Program is an equation compiled once to be evaluated many times with different variable bindings.
+// Programs are evaluated in float64 by the stack machine of package vm, no matter the backend of the Engine that compiled them.
+// A Program is immutable, it's safe for concurrent use by multiple goroutines.
+type Program struct {
+    root   ast.Node
+    code   *vm.Bytecode
+    stacks sync.Pool
+}
+
+// Compile compiles an equation with a new Engine, which has the built-in functions only.
+func Compile(equation string) (*Program, error) {
+    return New().Compile(equation)
+}
+
+// Compile compiles an equation into a Program.
+// The equation is simplified by package optimizer before it's lowered to bytecode.
+// Identifiers that aren't constants of the Engine are free variables, bound when the Program is evaluated.
+// Constants, functions, 'ans' and the values of variables are taken from the Engine when the equation is compiled,
+// later changes to the Engine don't affect the Program.
+// Assignments can't be compiled, since Programs don't change any variables.
+func (e *Engine) Compile(equation string) (*Program, error) {
+    n, err := e.p.ParseEquation(equation)
+    if err != nil {
+        return nil, err
+    }
+    code, err := vm.Compile(optimizer.Optimize(n, e.p.Environment()), e.p.Environment())
+    if err != nil {
+        return nil, err
+    }
+
+    p := &Program{root: n, code: code}
+    p.stacks.New = func() any {
+        stack := make([]float64, code.StackSize)
+        return &stack
+    }
+    return p, nil
+}
+
+// Eval evaluates the Program with the values of its free variables.
+// Variables without a binding fall back to their value in the Engine when the Program was compiled.
+// Eval doesn't allocate, unless it fails or calls a function without a float64 implementation.
+func (p *Program) Eval(bindings map[string]float64) (float64, error) {
+    stack := p.stacks.Get().(*[]float64)
+    result, err := vm.Run(p.code, bindings, *stack)
+    p.stacks.Put(stack)
+    return result, err
+}
+
+// Node returns the tree of the compiled equation, which must not be modified.
+func (p *Program) Node() ast.Node {
+    return p.root
+}
+
+// Bytecode returns the bytecode the Program runs, which must not be modified.
+func (p *Program) Bytecode() *vm.Bytecode {
+    return p.code
+}
+
+// Variables returns the names of the free variables in alphabetical order.
+func (p *Program) Variables() []string {
+    names := make([]string, len(p.code.Variables))
+    for i, v := range p.code.Variables {
+        names[i] = v.Name
+    }
+    sort.Strings(names)
+    return names
+}
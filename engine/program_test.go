@@ -0,0 +1,148 @@
+package engine
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/number"
+    "LexicalCalculator/parser"
+    "LexicalCalculator/support"
+    "errors"
+    "strings"
+    "sync"
+    "testing"
+)
+
+func TestProgram_Eval(t *testing.T) {
+    e := New()
+    e.SetConstant("tax", 0.2)
+    e.SetVariable("discount", 5)
+    e.RegisterFunction("clamp", 3, func(args ...float64) (float64, error) {
+        return min(max(args[0], args[1]), args[2]), nil
+    })
+
+    prog, err := e.Compile("clamp(price * quantity * (1 + tax) - discount, 0, max(limit, sqrt(16)))")
+    if err != nil {
+        t.Fatalf("Error compiling: got error %v.\n", err)
+    }
+    if strings.Join(prog.Variables(), " ") != "discount limit price quantity" {
+        t.Errorf("Error free variables: expected discount limit price quantity, got %v.\n", prog.Variables())
+    }
+
+    testCases := []struct {
+        bindings map[string]float64
+        result   float64
+    }{
+        {bindings: map[string]float64{"price": 10, "quantity": 2, "limit": 100}, result: 19},
+        {bindings: map[string]float64{"price": 10, "quantity": 2, "limit": 100, "discount": 0}, result: 24},
+        {bindings: map[string]float64{"price": 100, "quantity": 2, "limit": 100}, result: 100},
+        {bindings: map[string]float64{"price": 1, "quantity": 1, "limit": 0}, result: 0},
+    }
+
+    for _, tc := range testCases {
+        re, err := prog.Eval(tc.bindings)
+        if err != nil {
+            t.Errorf("Error evaluating with %v: got error %v.\n", tc.bindings, err)
+            continue
+        }
+        if !support.AlmostEqual(re, tc.result, 0.0001) {
+            t.Errorf("Error evaluating with %v: expected %f, got %f.\n", tc.bindings, tc.result, re)
+        }
+    }
+
+    // The Program keeps the state of the Engine when it was compiled.
+    e.SetVariable("discount", 1000)
+    if re, _ := prog.Eval(testCases[0].bindings); !support.AlmostEqual(re, 19, 0.0001) {
+        t.Errorf("Error evaluating after changing the Engine: expected 19, got %f.\n", re)
+    }
+}
+
+func TestProgram_Eval_Error(t *testing.T) {
+    testCases := []struct {
+        input    string
+        bindings map[string]float64
+        err      error
+    }{
+        {input: "x / y", bindings: map[string]float64{"x": 1, "y": 0}, err: ast.ErrZeroDivision},
+        {input: "x + y", bindings: map[string]float64{"x": 1}, err: ast.ErrUndefinedVariable},
+        {input: "x ^ 0.5", bindings: map[string]float64{"x": -4}, err: number.ErrNotReal},
+        {input: "sqrt(x)", bindings: map[string]float64{"x": -4}, err: number.ErrNotReal},
+    }
+
+    for _, tc := range testCases {
+        prog, err := Compile(tc.input)
+        if err != nil {
+            t.Errorf("Error compiling %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        if _, err := prog.Eval(tc.bindings); !errors.Is(err, tc.err) {
+            t.Errorf("Error evaluating %s with %v: expected error %s, got %v.\n", tc.input, tc.bindings, tc.err, err)
+        }
+    }
+
+    compileErrors := []struct {
+        input string
+        err   error
+    }{
+        {input: "x = 2", err: ErrNotCompilable},
+        {input: "2i * x", err: ErrNotCompilable},
+        {input: "foo(x)", err: parser.ErrEquation},
+    }
+    for _, tc := range compileErrors {
+        if _, err := Compile(tc.input); !errors.Is(err, tc.err) {
+            t.Errorf("Error compiling %s: expected error %s, got %v.\n", tc.input, tc.err, err)
+        }
+    }
+}
+
+func TestProgram_Eval_Concurrent(t *testing.T) {
+    prog, err := Compile("hypot(x, y) + max(x, y, sqrt(x * y))")
+    if err != nil {
+        t.Fatalf("Error compiling: got error %v.\n", err)
+    }
+
+    var wg sync.WaitGroup
+    for i := 0; i < 8; i++ {
+        wg.Add(1)
+        go func(x float64) {
+            defer wg.Done()
+            bindings := map[string]float64{"x": x, "y": 0}
+            for j := 0; j < 1000; j++ {
+                re, err := prog.Eval(bindings)
+                if err != nil || re != 2*x {
+                    t.Errorf("Error evaluating with x = %f: expected %f, got %f, error %v.\n", x, 2*x, re, err)
+                    return
+                }
+            }
+        }(float64(i))
+    }
+    wg.Wait()
+}
+
+func TestProgram_Eval_Allocs(t *testing.T) {
+    bindings := map[string]float64{"principal": 1000, "rate": 0.05, "years": 10, "fee": 3}
+    for _, equation := range []string{
+        "principal * (1 + rate / 12) ^ (12 * years) + max(fee, 1, abs(-2))",
+        "principal // rate + principal % fee - years rem 3",
+    } {
+        prog, err := Compile(equation)
+        if err != nil {
+            t.Fatalf("Error compiling %s: got error %v.\n", equation, err)
+        }
+
+        allocs := testing.AllocsPerRun(100, func() {
+            prog.Eval(bindings)
+        })
+        if allocs != 0 {
+            t.Errorf("Error allocations of Eval of %s: expected 0, got %f.\n", equation, allocs)
+        }
+    }
+}
+
+func BenchmarkProgram_Eval(b *testing.B) {
+    prog, _ := Compile("principal * (1 + rate / 12) ^ (12 * years) + max(fee, 1, abs(-2))")
+    bindings := map[string]float64{"principal": 1000, "rate": 0.05, "years": 10, "fee": 3}
+
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        prog.Eval(bindings)
+    }
+}
@@ -0,0 +1,437 @@
+/*
+Package format renders the results of equations as text, in decimal, scientific or engineering notation,
+in the integer bases of programmers, or as exact fractions.
+
+    f, _ := format.New(format.ENGINEERING, 3)
+    s, err := f.Text(number.Float(0.000047)) // s: 47.0µ
+
+The zero Format is the default notation of the calculator, where float64 and complex results are rounded to 4 decimal places
+and the results of the arbitrary-precision backends are printed in full. The auto notation trims the trailing zeros of the rounded results,
+like '2' instead of '2.0000', and rounds big.Float results too. Exact results, rationals and integers, are printed in full by both.
+
+Numbers are rounded half to even unless the Format has another rounding mode:
+
+    f := format.Format{Notation: format.FIXED, Digits: 2, Rounding: format.HALF_UP}
+    s, err := f.Text(number.Float(2.675)) // s: 2.68
+*/
+package format
+
+import (
+    "LexicalCalculator/number"
+    "errors"
+    "fmt"
+    "math"
+    "math/big"
+    "strconv"
+    "strings"
+)
+
+// The notations of a Format.
+const (
+    DEFAULT     = "default"
+    AUTO        = "auto"
+    FIXED       = "fixed"
+    SIGNIFICANT = "significant"
+    SCIENTIFIC  = "scientific"
+    ENGINEERING = "engineering"
+    HEX         = "hex"
+    BINARY      = "binary"
+    OCTAL       = "octal"
+    FRACTION    = "fraction"
+
+    // DefaultPlaces is the number of decimal places of the default and the fixed notation, if none is given.
+    DefaultPlaces = 4
+    // AutoPlaces is the number of decimal places of the auto notation, if none is given.
+    AutoPlaces = 10
+    // MaxDigits is the largest number of digits of a Format.
+    MaxDigits = 1000
+)
+
+// The rounding modes of a Format.
+const (
+    HALF_EVEN   = "half-even"   // To the nearest, halves to the even neighbour.
+    HALF_UP     = "half-up"     // To the nearest, halves away from zero.
+    TOWARD_ZERO = "toward-zero" // Truncated.
+    FLOOR       = "floor"       // Toward negative infinity.
+    CEILING     = "ceiling"     // Toward positive infinity.
+)
+
+var (
+    ErrUnknownNotation = errors.New("error unknown notation")
+    ErrInvalidDigits   = errors.New("error invalid number of digits")
+    ErrUnknownRounding = errors.New("error unknown rounding mode")
+)
+
+// bases maps the notations of integer bases to their base and prefix.
+var bases = map[string]struct {
+    base   int
+    prefix string
+}{
+    HEX:    {base: 16, prefix: "0x"},
+    BINARY: {base: 2, prefix: "0b"},
+    OCTAL:  {base: 8, prefix: "0o"},
+}
+
+// siPrefixes maps the exponents of the engineering notation to their SI prefixes.
+var siPrefixes = map[int]string{
+    -30: "q", -27: "r", -24: "y", -21: "z", -18: "a", -15: "f", -12: "p", -9: "n", -6: "µ", -3: "m",
+    0: "", 3: "k", 6: "M", 9: "G", 12: "T", 15: "P", 18: "E", 21: "Z", 24: "Y", 27: "R", 30: "Q",
+}
+
+// Format is a way of rendering numbers as text.
+type Format struct {
+    // Notation is one of the notations, the empty notation is DEFAULT.
+    Notation string
+    // Digits is the number of decimal places of FIXED and AUTO, and the number of significant digits of SIGNIFICANT, SCIENTIFIC and ENGINEERING,
+    // where 0 means as many digits as it takes to tell the number apart. The other notations ignore it.
+    Digits int
+    // Separator groups the digits of integer parts by thousands, like ',' in '1,000,000'. Numbers aren't grouped if it's empty.
+    // Integer bases, fractional parts and exponents are never grouped.
+    Separator string
+    // Rounding is the rounding mode of the decimal notations, the empty rounding mode is HALF_EVEN.
+    // Numbers with as many digits as it takes to tell them apart aren't rounded.
+    Rounding string
+}
+
+// New creates a Format of a notation.
+// Negative digits are the default of the notation, DefaultPlaces for FIXED, AutoPlaces for AUTO and as many as needed for the others.
+func New(notation string, digits int) (Format, error) {
+    switch notation {
+    case "", DEFAULT, AUTO, FIXED, SIGNIFICANT, SCIENTIFIC, ENGINEERING, HEX, BINARY, OCTAL, FRACTION:
+    default:
+        return Format{}, fmt.Errorf("%w '%s'", ErrUnknownNotation, notation)
+    }
+    if digits < 0 {
+        digits = 0
+        switch notation {
+        case FIXED:
+            digits = DefaultPlaces
+        case AUTO:
+            digits = AutoPlaces
+        }
+    }
+    if digits > MaxDigits {
+        return Format{}, fmt.Errorf("%w %d", ErrInvalidDigits, digits)
+    }
+    return Format{Notation: notation, Digits: digits}, nil
+}
+
+// SetRounding sets the rounding mode of a Format, the empty rounding mode is HALF_EVEN.
+func (f *Format) SetRounding(rounding string) error {
+    switch rounding {
+    case "", HALF_EVEN, HALF_UP, TOWARD_ZERO, FLOOR, CEILING:
+        f.Rounding = rounding
+        return nil
+    default:
+        return fmt.Errorf("%w '%s'", ErrUnknownRounding, rounding)
+    }
+}
+
+// WithPrecision returns a Format with the given digits.
+// The default notation, which always has DefaultPlaces, becomes AUTO, which rounds big.Float results to the digits as well.
+// Exact results, rationals like 1/3 and integers, are still printed in full.
+func (f Format) WithPrecision(digits int) Format {
+    if f.Notation == "" || f.Notation == DEFAULT {
+        f.Notation = AUTO
+    }
+    f.Digits = digits
+    return f
+}
+
+// String returns the description of a Format, like 'fixed 2' or 'engineering, separator ','.
+func (f Format) String() string {
+    s := f.Notation
+    switch s {
+    case "":
+        s = DEFAULT
+    case FIXED, AUTO, SIGNIFICANT, SCIENTIFIC, ENGINEERING:
+        if f.Notation == FIXED || f.Notation == AUTO || f.Digits > 0 {
+            s += " " + strconv.Itoa(f.Digits)
+        }
+    }
+    if f.Separator != "" {
+        s += fmt.Sprintf(", separator '%s'", f.Separator)
+    }
+    if f.Rounding != "" {
+        s += ", rounding " + f.Rounding
+    }
+    return s
+}
+
+// Text returns a number in the Format.
+// Integer bases need integers and fractions need real numbers, other notations render complex numbers like 'a + bi'.
+func (f Format) Text(n number.Number) (string, error) {
+    switch f.Notation {
+    case "", DEFAULT, AUTO:
+        switch n.(type) {
+        case number.Float, number.Complex:
+        case number.BigFloat:
+            // big.Float results are printed in full by default, the digits of AUTO are a precision asked for.
+            if f.Notation != AUTO {
+                return f.group(n.String()), nil
+            }
+        default:
+            // Exact results are printed in full, rounding 1/3 would lose it.
+            return f.group(n.String()), nil
+        }
+    case HEX, BINARY, OCTAL:
+        i, err := integer(n)
+        if err != nil {
+            return "", fmt.Errorf("%w: %s notation of %v", err, f.Notation, n)
+        }
+        b := bases[f.Notation]
+        if i.Sign() < 0 {
+            return "-" + b.prefix + new(big.Int).Neg(i).Text(b.base), nil
+        }
+        return b.prefix + i.Text(b.base), nil
+    case FRACTION:
+        r, err := number.RatBackend.Convert(n)
+        if err != nil {
+            return "", fmt.Errorf("%w: fraction of %v", err, n)
+        }
+        return f.group(r.(number.Rat).Rat().RatString()), nil
+    }
+
+    if c, ok := n.(number.Complex); ok {
+        if imag(c) == 0 {
+            return f.Text(number.Float(real(c)))
+        }
+        re, _ := f.Text(number.Float(real(c)))
+        im, _ := f.Text(number.Float(math.Abs(imag(c))))
+        if math.Signbit(imag(c)) {
+            return re + " - " + im + "i", nil
+        }
+        return re + " + " + im + "i", nil
+    }
+    if x := n.Float64(); math.IsNaN(x) || math.IsInf(x, 0) {
+        return strconv.FormatFloat(x, 'g', -1, 64), nil
+    }
+
+    switch f.Notation {
+    case "", DEFAULT:
+        return f.group(fixed(n, DefaultPlaces, f.Rounding)), nil
+    case AUTO:
+        return f.group(trimZeros(fixed(n, f.Digits, f.Rounding))), nil
+    case FIXED:
+        return f.group(fixed(n, f.Digits, f.Rounding)), nil
+    case SIGNIFICANT:
+        sign, digits, exponent := significant(n, f.Digits, f.Rounding)
+        return f.group(sign + point(digits, exponent+1)), nil
+    case SCIENTIFIC:
+        sign, digits, exponent := significant(n, f.Digits, f.Rounding)
+        mantissa := digits[:1]
+        if len(digits) > 1 {
+            mantissa += "." + digits[1:]
+        }
+        return fmt.Sprintf("%s%se%+03d", sign, mantissa, exponent), nil
+    case ENGINEERING:
+        sign, digits, exponent := significant(n, f.Digits, f.Rounding)
+        // The exponent is rounded down to a multiple of 3, leaving 1 to 3 digits before the decimal point.
+        e3 := exponent - ((exponent%3)+3)%3
+        mantissa := sign + point(digits, exponent-e3+1)
+        if prefix, ok := siPrefixes[e3]; ok {
+            return mantissa + prefix, nil
+        }
+        return fmt.Sprintf("%se%d", mantissa, e3), nil
+    default:
+        return "", fmt.Errorf("%w '%s'", ErrUnknownNotation, f.Notation)
+    }
+}
+
+// group inserts the separator between the thousands of the integer parts of a text, like the numerator and denominator of '1000/3'.
+// Digits after a decimal point or an exponent aren't grouped.
+func (f Format) group(s string) string {
+    if f.Separator == "" {
+        return s
+    }
+
+    var b strings.Builder
+    for i := 0; i < len(s); {
+        j := i
+        for j < len(s) && isDigit(s[j]) {
+            j++
+        }
+        if j == i {
+            b.WriteByte(s[i])
+            i++
+            continue
+        }
+        run := s[i:j]
+        if !isFractionOrExponent(s, i) {
+            for k := len(run) % 3; k < len(run); k += 3 {
+                if k > 0 {
+                    run = run[:k] + f.Separator + run[k:]
+                    k += len(f.Separator)
+                }
+            }
+        }
+        b.WriteString(run)
+        i = j
+    }
+    return b.String()
+}
+
+// isFractionOrExponent checks whether the digits starting at i in a text are a fractional part or an exponent.
+func isFractionOrExponent(s string, i int) bool {
+    if i > 0 && (s[i-1] == '+' || s[i-1] == '-') {
+        i--
+    }
+    return i > 0 && (s[i-1] == '.' || s[i-1] == 'e' || s[i-1] == 'E')
+}
+
+// fixed returns a number rounded to the given decimal places.
+// The number is rounded exactly, no matter how many digits it has, and a rounded 0 has no sign.
+func fixed(n number.Number, places int, rounding string) string {
+    q := round(exact(n), places, rounding)
+    sign := ""
+    if q.Sign() < 0 {
+        sign = "-"
+        q.Neg(q)
+    }
+    digits := q.String()
+    return sign + point(digits, len(digits)-places)
+}
+
+// trimZeros removes the trailing zeros of the fractional part of a number, and the decimal point if nothing is left after it.
+func trimZeros(s string) string {
+    if !strings.Contains(s, ".") {
+        return s
+    }
+    return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// significant rounds a number to the given significant digits, 0 for as many as it takes to tell the number apart.
+// It returns the sign, the digits and the decimal exponent of the first digit, like '-', '15', 2 for -150.
+func significant(n number.Number, digits int, rounding string) (string, string, int) {
+    if digits == 0 {
+        s := decimal(n).Text('e', -1)
+        sign := ""
+        if strings.HasPrefix(s, "-") {
+            sign, s = "-", s[1:]
+        }
+        mantissa, exponent, _ := strings.Cut(s, "e")
+        e, _ := strconv.Atoi(exponent)
+        return sign, strings.Replace(mantissa, ".", "", 1), e
+    }
+
+    r := exact(n)
+    if r.Sign() == 0 {
+        return "", strings.Repeat("0", digits), 0
+    }
+    sign := ""
+    if r.Sign() < 0 {
+        sign = "-"
+    }
+    // The first digit is at the difference between the lengths of the numerator and the denominator, or one place below.
+    // Rounding can carry into a new first digit, like 9.996 becoming 10.0, so the exponent is corrected until the digits fit.
+    e := len(new(big.Int).Abs(r.Num()).String()) - len(r.Denom().String())
+    for {
+        q := round(r, digits-1-e, rounding)
+        s := new(big.Int).Abs(q).String()
+        switch {
+        case len(s) > digits:
+            e++
+        case len(s) < digits || q.Sign() == 0:
+            e--
+        default:
+            return sign, s, e
+        }
+    }
+}
+
+// round rounds a rational number to the given decimal places, negative places round to tens, hundreds and so on.
+// It returns the rounded number times 10^places.
+func round(r *big.Rat, places int, rounding string) *big.Int {
+    scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(places))), nil))
+    scaled := new(big.Rat)
+    if places >= 0 {
+        scaled.Mul(r, scale)
+    } else {
+        scaled.Quo(r, scale)
+    }
+
+    q, m := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+    if m.Sign() == 0 {
+        return q
+    }
+    // Twice the truncated remainder is less than, equal to or greater than the denominator below, at or above a half.
+    half := new(big.Int).Lsh(m.Abs(m), 1).Cmp(scaled.Denom())
+    var away bool
+    switch rounding {
+    case TOWARD_ZERO:
+        away = false
+    case FLOOR:
+        away = r.Sign() < 0
+    case CEILING:
+        away = r.Sign() > 0
+    case HALF_UP:
+        away = half >= 0
+    default:
+        away = half > 0 || half == 0 && q.Bit(0) == 1
+    }
+    if away {
+        q.Add(q, big.NewInt(int64(r.Sign())))
+    }
+    return q
+}
+
+// point places the decimal point after the given number of digits, padding with zeros on either side.
+func point(digits string, position int) string {
+    switch {
+    case position <= 0:
+        return "0." + strings.Repeat("0", -position) + digits
+    case position >= len(digits):
+        return digits + strings.Repeat("0", position-len(digits))
+    default:
+        return digits[:position] + "." + digits[position:]
+    }
+}
+
+// decimal converts a finite real number into a big.Float, whose shortest digits tell the number apart.
+// Numbers already in binary floating-point keep their precision, so their shortest digits are the ones of their backend.
+// Rationals get the precision of a float64, instead of the endless digits of numbers like 1/3.
+func decimal(n number.Number) *big.Float {
+    switch n := n.(type) {
+    case number.BigFloat:
+        return n.BigFloat()
+    case number.Rat:
+        return new(big.Float).SetPrec(53).SetRat(n.Rat())
+    case number.Int:
+        return new(big.Float).SetInt64(int64(n))
+    default:
+        return big.NewFloat(n.Float64())
+    }
+}
+
+// exact converts a finite real number into a big.Rat, float64 numbers by their shortest decimal representation.
+// Rounding the shortest representation rounds 2.675 half up to 2.68, like it reads, instead of its binary value 2.67499999999999982236431605997495353221893310546875.
+func exact(n number.Number) *big.Rat {
+    r, _ := number.RatBackend.Convert(n)
+    return r.(number.Rat).Rat()
+}
+
+// integer converts an integral real number into a big.Int.
+func integer(n number.Number) (*big.Int, error) {
+    r, err := number.RatBackend.Convert(n)
+    if err != nil {
+        return nil, err
+    }
+    rat := r.(number.Rat).Rat()
+    if !rat.IsInt() {
+        return nil, number.ErrNotAnInteger
+    }
+    return rat.Num(), nil
+}
+
+// abs returns the absolute value of an int.
+func abs(i int) int {
+    if i < 0 {
+        return -i
+    }
+    return i
+}
+
+// isDigit determines whether a character is a decimal digit.
+func isDigit(ch byte) bool {
+    return '0' <= ch && ch <= '9'
+}
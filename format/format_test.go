@@ -0,0 +1,220 @@
+package format
+
+import (
+    "LexicalCalculator/number"
+    "errors"
+    "math"
+    "math/big"
+    "testing"
+)
+
+func TestFormat_Text(t *testing.T) {
+    testCases := []struct {
+        backend   number.Backend
+        input     string
+        notation  string
+        digits    int
+        separator string
+        result    string
+        err       error
+    }{
+        {backend: number.FloatBackend, input: "1234.56789", notation: DEFAULT, digits: -1, result: "1234.5679"},
+        {backend: number.FloatBackend, input: "1234567.891", notation: DEFAULT, digits: -1, separator: ",", result: "1,234,567.8910"},
+        {backend: number.FloatBackend, input: "2.5", notation: FIXED, digits: -1, result: "2.5000"},
+        {backend: number.FloatBackend, input: "999.9996", notation: FIXED, digits: 2, separator: ",", result: "1,000.00"},
+        {backend: number.RatBackend, input: "2/3", notation: FIXED, digits: 30, result: "0.666666666666666666666666666667"},
+        {backend: number.IntBackend, input: "-7", notation: FIXED, digits: 1, result: "-7.0"},
+        {backend: number.FloatBackend, input: "0.000047", notation: SIGNIFICANT, digits: 3, result: "0.0000470"},
+        {backend: number.FloatBackend, input: "1234567.891", notation: SIGNIFICANT, digits: 3, separator: ",", result: "1,230,000"},
+        {backend: number.RatBackend, input: "1/3", notation: SIGNIFICANT, digits: -1, result: "0.3333333333333333"},
+        {backend: number.FloatBackend, input: "-255", notation: SCIENTIFIC, digits: -1, result: "-2.55e+02"},
+        {backend: number.FloatBackend, input: "1234567.891", notation: SCIENTIFIC, digits: 3, separator: ",", result: "1.23e+06"},
+        {backend: number.NewBigFloatBackend(256), input: "0.1", notation: SCIENTIFIC, digits: 25, result: "1.000000000000000000000000e-01"},
+        {backend: number.FloatBackend, input: "0.000047", notation: ENGINEERING, digits: -1, result: "47µ"},
+        {backend: number.FloatBackend, input: "0.000047", notation: ENGINEERING, digits: 3, result: "47.0µ"},
+        {backend: number.FloatBackend, input: "1234567.891", notation: ENGINEERING, digits: 3, result: "1.23M"},
+        {backend: number.FloatBackend, input: "999.9996", notation: ENGINEERING, digits: 3, result: "1.00k"},
+        {backend: number.FloatBackend, input: "-255", notation: ENGINEERING, digits: -1, result: "-255"},
+        {backend: number.RatBackend, input: "1/3", notation: ENGINEERING, digits: 3, result: "333m"},
+        {backend: number.FloatBackend, input: "1e40", notation: ENGINEERING, digits: 3, result: "10.0e39"},
+        {backend: number.FloatBackend, input: "0", notation: ENGINEERING, digits: -1, result: "0"},
+        {backend: number.IntBackend, input: "255", notation: HEX, digits: -1, result: "0xff"},
+        {backend: number.IntBackend, input: "-255", notation: HEX, digits: -1, separator: ",", result: "-0xff"},
+        {backend: number.FloatBackend, input: "10", notation: BINARY, digits: -1, result: "0b1010"},
+        {backend: number.RatBackend, input: "8", notation: OCTAL, digits: -1, result: "0o10"},
+        {backend: number.FloatBackend, input: "1e20", notation: HEX, digits: -1, result: "0x56bc75e2d63100000"},
+        {backend: number.FloatBackend, input: "2.5", notation: HEX, digits: -1, err: number.ErrNotAnInteger},
+        {backend: number.RatBackend, input: "0.75", notation: FRACTION, digits: -1, result: "3/4"},
+        {backend: number.FloatBackend, input: "999.9996", notation: FRACTION, digits: -1, separator: ",", result: "2,499,999/2,500"},
+        {backend: number.IntBackend, input: "-3", notation: FRACTION, digits: -1, result: "-3"},
+    }
+
+    for _, tc := range testCases {
+        n, err := tc.backend.Parse(tc.input)
+        if err != nil {
+            t.Fatalf("Error parsing %s: got error %v.\n", tc.input, err)
+        }
+        f, err := New(tc.notation, tc.digits)
+        if err != nil {
+            t.Fatalf("Error creating %s %d: got error %v.\n", tc.notation, tc.digits, err)
+        }
+        f.Separator = tc.separator
+        s, err := f.Text(n)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error %s of %s: expected error %v, got %v.\n", f, tc.input, tc.err, err)
+            continue
+        }
+        if s != tc.result {
+            t.Errorf("Error %s of %s: expected %s, got %s.\n", f, tc.input, tc.result, s)
+        }
+    }
+}
+
+func TestFormat_Text_Rounding(t *testing.T) {
+    testCases := []struct {
+        input    number.Number
+        notation string
+        digits   int
+        rounding string
+        result   string
+    }{
+        {input: number.Float(2.675), notation: FIXED, digits: 2, rounding: "", result: "2.68"},
+        {input: number.Float(2.665), notation: FIXED, digits: 2, rounding: HALF_EVEN, result: "2.66"},
+        {input: number.Float(2.665), notation: FIXED, digits: 2, rounding: HALF_UP, result: "2.67"},
+        {input: number.Float(-2.665), notation: FIXED, digits: 2, rounding: HALF_UP, result: "-2.67"},
+        {input: number.Float(2.669), notation: FIXED, digits: 2, rounding: TOWARD_ZERO, result: "2.66"},
+        {input: number.Float(-2.661), notation: FIXED, digits: 2, rounding: FLOOR, result: "-2.67"},
+        {input: number.Float(2.661), notation: FIXED, digits: 2, rounding: CEILING, result: "2.67"},
+        {input: number.Float(-2.669), notation: FIXED, digits: 2, rounding: CEILING, result: "-2.66"},
+        {input: number.Float(-0.001), notation: FIXED, digits: 2, rounding: "", result: "0.00"},
+        {input: number.Float(2.5), notation: FIXED, digits: 0, rounding: "", result: "2"},
+        {input: number.Float(3.5), notation: FIXED, digits: 0, rounding: "", result: "4"},
+        {input: number.NewRat(big.NewRat(1, 8)), notation: FIXED, digits: 2, rounding: "", result: "0.12"},
+        {input: number.NewRat(big.NewRat(1, 8)), notation: FIXED, digits: 2, rounding: HALF_UP, result: "0.13"},
+        {input: number.Float(0.00001), notation: DEFAULT, digits: 0, rounding: CEILING, result: "0.0001"},
+        {input: number.Float(2), notation: AUTO, digits: AutoPlaces, rounding: "", result: "2"},
+        {input: number.Float(0.00001), notation: AUTO, digits: AutoPlaces, rounding: "", result: "0.00001"},
+        {input: number.Float(0.1 + 0.2), notation: AUTO, digits: AutoPlaces, rounding: "", result: "0.3"},
+        {input: number.Float(1.0 / 3), notation: AUTO, digits: 3, rounding: CEILING, result: "0.334"},
+        {input: number.Complex(2 + 0.5i), notation: AUTO, digits: 3, rounding: "", result: "2 + 0.5i"},
+        {input: number.Int(7), notation: AUTO, digits: 3, rounding: "", result: "7"},
+        {input: number.Int(-1234567), notation: AUTO, digits: 2, rounding: "", result: "-1234567"},
+        {input: number.NewRat(big.NewRat(1, 3)), notation: AUTO, digits: 2, rounding: "", result: "1/3"},
+        {input: number.NewBigFloat(big.NewFloat(2.5)), notation: AUTO, digits: 2, rounding: "", result: "2.5"},
+        {input: number.NewBigFloat(big.NewFloat(1.0 / 3)), notation: AUTO, digits: 2, rounding: "", result: "0.33"},
+        {input: number.NewBigFloat(big.NewFloat(2.675)), notation: AUTO, digits: 2, rounding: TOWARD_ZERO, result: "2.67"},
+        {input: number.NewBigFloat(big.NewFloat(1.0 / 3)), notation: DEFAULT, digits: 0, rounding: "", result: "0.3333333333333333"},
+        {input: number.Float(9.996), notation: SIGNIFICANT, digits: 3, rounding: "", result: "10.0"},
+        {input: number.Float(9.996), notation: SIGNIFICANT, digits: 3, rounding: TOWARD_ZERO, result: "9.99"},
+        {input: number.Float(-1234), notation: SIGNIFICANT, digits: 2, rounding: FLOOR, result: "-1300"},
+        {input: number.Float(0.0001234), notation: SCIENTIFIC, digits: 2, rounding: CEILING, result: "1.3e-04"},
+        {input: number.Float(1.5e100), notation: SCIENTIFIC, digits: 1, rounding: "", result: "2e+100"},
+        {input: number.Float(2.5e-7), notation: SCIENTIFIC, digits: 1, rounding: "", result: "2e-07"},
+        {input: number.Float(999.95), notation: ENGINEERING, digits: 4, rounding: HALF_UP, result: "1.000k"},
+        {input: number.Float(999.95), notation: ENGINEERING, digits: 4, rounding: TOWARD_ZERO, result: "999.9"},
+    }
+
+    for _, tc := range testCases {
+        f := Format{Notation: tc.notation, Digits: tc.digits}
+        if err := f.SetRounding(tc.rounding); err != nil {
+            t.Fatalf("Error setting rounding %s: got error %v.\n", tc.rounding, err)
+        }
+        s, err := f.Text(tc.input)
+        if err != nil {
+            t.Errorf("Error %s of %v: got error %v.\n", f, tc.input, err)
+            continue
+        }
+        if s != tc.result {
+            t.Errorf("Error %s of %v: expected %s, got %s.\n", f, tc.input, tc.result, s)
+        }
+    }
+}
+
+func TestFormat_Text_Special(t *testing.T) {
+    testCases := []struct {
+        input     number.Number
+        notation  string
+        separator string
+        result    string
+        err       error
+    }{
+        {input: number.NewRat(big.NewRat(1000000, 3)), notation: DEFAULT, separator: "_", result: "1_000_000/3"},
+        {input: number.Complex(1 - 2i), notation: DEFAULT, result: "1.0000 - 2.0000i"},
+        {input: number.Complex(1.5 + 2.25i), notation: ENGINEERING, result: "1.5 + 2.25i"},
+        {input: number.Complex(-1000i), notation: SCIENTIFIC, result: "0e+00 - 1e+03i"},
+        {input: number.Float(math.Inf(-1)), notation: SCIENTIFIC, result: "-Inf"},
+        {input: number.Float(math.NaN()), notation: ENGINEERING, result: "NaN"},
+        {input: number.Complex(2i), notation: BINARY, err: number.ErrNotReal},
+        {input: number.Complex(2i), notation: FRACTION, err: number.ErrNotReal},
+    }
+
+    for _, tc := range testCases {
+        s, err := Format{Notation: tc.notation, Separator: tc.separator}.Text(tc.input)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error %s of %v: expected error %v, got %v.\n", tc.notation, tc.input, tc.err, err)
+            continue
+        }
+        if s != tc.result {
+            t.Errorf("Error %s of %v: expected %s, got %s.\n", tc.notation, tc.input, tc.result, s)
+        }
+    }
+}
+
+func TestNew(t *testing.T) {
+    testCases := []struct {
+        notation string
+        digits   int
+        result   string
+        err      error
+    }{
+        {notation: "", digits: -1, result: "default"},
+        {notation: FIXED, digits: -1, result: "fixed 4"},
+        {notation: FIXED, digits: 0, result: "fixed 0"},
+        {notation: SIGNIFICANT, digits: 6, result: "significant 6"},
+        {notation: ENGINEERING, digits: -1, result: "engineering"},
+        {notation: HEX, digits: 3, result: "hex"},
+        {notation: AUTO, digits: -1, result: "auto 10"},
+        {notation: "roman", digits: -1, err: ErrUnknownNotation},
+        {notation: FIXED, digits: 1001, err: ErrInvalidDigits},
+    }
+
+    for _, tc := range testCases {
+        f, err := New(tc.notation, tc.digits)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error creating %s %d: expected error %v, got %v.\n", tc.notation, tc.digits, tc.err, err)
+            continue
+        }
+        if err == nil && f.String() != tc.result {
+            t.Errorf("Error creating %s %d: expected %s, got %s.\n", tc.notation, tc.digits, tc.result, f)
+        }
+    }
+}
+
+func TestFormat_SetRounding(t *testing.T) {
+    f := Format{Separator: ","}
+    if err := f.SetRounding("sideways"); !errors.Is(err, ErrUnknownRounding) {
+        t.Errorf("Error setting rounding sideways: expected error %v, got %v.\n", ErrUnknownRounding, err)
+    }
+    if err := f.SetRounding(FLOOR); err != nil || f.String() != "default, separator ',', rounding floor" {
+        t.Errorf("Error setting rounding floor: expected default, separator ',', rounding floor, got %s, error %v.\n", f, err)
+    }
+}
+
+func TestFormat_WithPrecision(t *testing.T) {
+    testCases := []struct {
+        format Format
+        digits int
+        result string
+    }{
+        {format: Format{}, digits: 6, result: "auto 6"},
+        {format: Format{Notation: DEFAULT, Rounding: HALF_UP}, digits: 2, result: "auto 2, rounding half-up"},
+        {format: Format{Notation: FIXED, Digits: 4}, digits: 2, result: "fixed 2"},
+        {format: Format{Notation: SCIENTIFIC}, digits: 3, result: "scientific 3"},
+    }
+
+    for _, tc := range testCases {
+        if f := tc.format.WithPrecision(tc.digits); f.String() != tc.result {
+            t.Errorf("Error precision %d of %s: expected %s, got %s.\n", tc.digits, tc.format, tc.result, f)
+        }
+    }
+}
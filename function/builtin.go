@@ -0,0 +1,211 @@
+package function
+
+import (
+    "LexicalCalculator/number"
+    "math"
+    "math/big"
+    "math/cmplx"
+)
+
+// builtins stores the built-in functions by name.
+var builtins = map[string]*Function{}
+
+func init() {
+    for _, f := range []*Function{
+        unary("sqrt", math.Sqrt, cmplx.Sqrt),
+        unary("cbrt", math.Cbrt, func(z complex128) complex128 { return cmplx.Pow(z, 1.0/3) }),
+        unary("exp", math.Exp, cmplx.Exp),
+        unary("ln", math.Log, cmplx.Log),
+        unary("log10", math.Log10, cmplx.Log10),
+        unary("log2", math.Log2, func(z complex128) complex128 { return cmplx.Log(z) / math.Ln2 }),
+        unary("sin", math.Sin, cmplx.Sin),
+        unary("cos", math.Cos, cmplx.Cos),
+        unary("tan", math.Tan, cmplx.Tan),
+        unary("asin", math.Asin, cmplx.Asin),
+        unary("acos", math.Acos, cmplx.Acos),
+        unary("atan", math.Atan, cmplx.Atan),
+        unary("sinh", math.Sinh, cmplx.Sinh),
+        unary("cosh", math.Cosh, cmplx.Cosh),
+        unary("tanh", math.Tanh, cmplx.Tanh),
+        unary("asinh", math.Asinh, cmplx.Asinh),
+        unary("acosh", math.Acosh, cmplx.Acosh),
+        unary("atanh", math.Atanh, cmplx.Atanh),
+        rounding("floor", math.Floor, floorRat),
+        rounding("ceil", math.Ceil, ceilRat),
+        rounding("round", math.Round, roundRat),
+        rounding("trunc", math.Trunc, truncRat),
+        {Name: "abs", MinArgs: 1, MaxArgs: 1, Call: abs, Real: realUnary(math.Abs)},
+        {Name: "min", MinArgs: 1, MaxArgs: Variadic, Call: extreme(-1), Real: realExtreme(-1)},
+        {Name: "max", MinArgs: 1, MaxArgs: Variadic, Call: extreme(+1), Real: realExtreme(+1)},
+        {Name: "hypot", MinArgs: 2, MaxArgs: 2, Call: hypot, Real: realHypot},
+    } {
+        builtins[f.Name] = f
+    }
+}
+
+// unary creates a Function of one argument from a math function and its complex counterpart.
+// Real arguments are calculated with the math function and the result is converted to the backend of the argument.
+// Complex arguments, and real arguments outside the real domain of the function, like sqrt(-4) or asin(2),
+// are calculated with the complex function instead.
+func unary(name string, real func(float64) float64, complexFn func(complex128) complex128) *Function {
+    return &Function{
+        Name:    name,
+        MinArgs: 1,
+        MaxArgs: 1,
+        Call: func(args []number.Number) (number.Number, error) {
+            x := args[0]
+            if c, ok := x.(number.Complex); ok {
+                return number.Complex(complexFn(complex128(c))), nil
+            }
+
+            // big.Float calculates square roots in full precision.
+            if b, ok := x.(number.BigFloat); ok && name == "sqrt" && b.BigFloat().Sign() >= 0 {
+                f := b.BigFloat()
+                return number.NewBigFloat(new(big.Float).SetPrec(f.Prec()).Sqrt(f)), nil
+            }
+
+            result := real(x.Float64())
+            if math.IsNaN(result) && !math.IsNaN(x.Float64()) {
+                return number.Complex(complexFn(complex(x.Float64(), 0))), nil
+            }
+            return number.BackendOf(x).Convert(number.Float(result))
+        },
+        Real: realUnary(real),
+    }
+}
+
+// realUnary creates the Real implementation of a math function of one argument.
+func realUnary(real func(float64) float64) func(args []float64) (float64, error) {
+    return func(args []float64) (float64, error) {
+        result := real(args[0])
+        if math.IsNaN(result) && !math.IsNaN(args[0]) {
+            return 0, number.ErrNotReal
+        }
+        return result, nil
+    }
+}
+
+// rounding creates a Function rounding its argument to an integer.
+// Rationals and big floats are rounded exactly with the rational function, then converted back to their backend.
+// Integers are already rounded.
+func rounding(name string, float func(float64) float64, rat func(*big.Rat) *big.Int) *Function {
+    return &Function{
+        Name:    name,
+        MinArgs: 1,
+        MaxArgs: 1,
+        Call: func(args []number.Number) (number.Number, error) {
+            switch x := args[0].(type) {
+            case number.Int:
+                return x, nil
+            case number.Float:
+                return number.Float(float(float64(x))), nil
+            case number.Complex:
+                if imag(x) != 0 {
+                    return nil, number.ErrNotReal
+                }
+                return number.Complex(complex(float(real(x)), 0)), nil
+            default:
+                r, err := number.RatBackend.Convert(x)
+                if err != nil {
+                    return nil, err
+                }
+                rounded := new(big.Rat).SetInt(rat(r.(number.Rat).Rat()))
+                return number.BackendOf(x).Convert(number.NewRat(rounded))
+            }
+        },
+        Real: realUnary(float),
+    }
+}
+
+// floorRat returns the greatest integer less than or equal to r.
+func floorRat(r *big.Rat) *big.Int {
+    // The denominator is always positive, so the Euclidean division rounds down.
+    return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+// ceilRat returns the least integer greater than or equal to r.
+func ceilRat(r *big.Rat) *big.Int {
+    return new(big.Int).Neg(floorRat(new(big.Rat).Neg(r)))
+}
+
+// truncRat returns the integer part of r.
+func truncRat(r *big.Rat) *big.Int {
+    return new(big.Int).Quo(r.Num(), r.Denom())
+}
+
+// roundRat returns the nearest integer to r, rounding half away from zero like math.Round.
+func roundRat(r *big.Rat) *big.Int {
+    half := big.NewRat(1, 2)
+    if r.Sign() < 0 {
+        half.Neg(half)
+    }
+    return truncRat(new(big.Rat).Add(r, half))
+}
+
+// abs returns the absolute value of its argument, the modulus for complex numbers.
+func abs(args []number.Number) (number.Number, error) {
+    if c, ok := args[0].(number.Complex); ok {
+        return number.Complex(complex(cmplx.Abs(complex128(c)), 0)), nil
+    }
+    sign, err := args[0].Cmp(number.Int(0))
+    if err != nil {
+        return nil, err
+    }
+    if sign < 0 {
+        return args[0].Neg()
+    }
+    return args[0], nil
+}
+
+// extreme returns a function returning the argument that compares to all others with the given sign,
+// which is the minimum for -1 and the maximum for +1.
+func extreme(sign int) func(args []number.Number) (number.Number, error) {
+    return func(args []number.Number) (number.Number, error) {
+        result := args[0]
+        for _, arg := range args[1:] {
+            cmp, err := arg.Cmp(result)
+            if err != nil {
+                return nil, err
+            }
+            if cmp == sign {
+                result = arg
+            }
+        }
+        // Comparing a single complex argument fails as well, it has no order.
+        if _, err := result.Cmp(result); err != nil {
+            return nil, err
+        }
+        return result, nil
+    }
+}
+
+// realExtreme is the Real implementation of extreme.
+func realExtreme(sign int) func(args []float64) (float64, error) {
+    return func(args []float64) (float64, error) {
+        result := args[0]
+        for _, arg := range args {
+            if math.IsNaN(arg) {
+                return 0, number.ErrNotANumber
+            }
+            if (sign < 0 && arg < result) || (sign > 0 && arg > result) {
+                result = arg
+            }
+        }
+        return result, nil
+    }
+}
+
+// hypot returns sqrt(x*x + y*y), in the backend of x.
+func hypot(args []number.Number) (number.Number, error) {
+    for _, arg := range args {
+        if c, ok := arg.(number.Complex); ok && imag(c) != 0 {
+            return nil, number.ErrNotReal
+        }
+    }
+    return number.BackendOf(args[0]).Convert(number.Float(math.Hypot(args[0].Float64(), args[1].Float64())))
+}
+
+// realHypot is the Real implementation of hypot.
+func realHypot(args []float64) (float64, error) {
+    return math.Hypot(args[0], args[1]), nil
+}
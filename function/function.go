@@ -0,0 +1,101 @@
+/*
+Package function implements the functions equations can call, like 'sqrt(2)' or 'max(1, 2, 3)'.
+
+The built-in functions take Numbers of any backend. Functions that the backends can't calculate exactly, like sqrt and sin,
+are calculated in float64, then converted to the backend of the argument.
+Results that aren't real, like the square root of a negative number, are promoted to complex numbers.
+*/
+package function
+
+import (
+    "LexicalCalculator/number"
+    "errors"
+    "fmt"
+    "sort"
+)
+
+const (
+    // Variadic is the MaxArgs of functions taking any number of arguments.
+    Variadic = -1
+)
+
+var (
+    ErrUnknownFunction = errors.New("error unknown function")
+    ErrArity           = errors.New("error wrong number of arguments")
+)
+
+// Function is a function that can be called in equations.
+type Function struct {
+    Name string
+    // MinArgs and MaxArgs bound the number of arguments, MaxArgs is Variadic if there is no upper bound.
+    MinArgs int
+    MaxArgs int
+    Call    func(args []number.Number) (number.Number, error)
+    // Real is the float64 implementation of the function, used where equations are evaluated in float64 only.
+    // It fails with number.ErrNotReal if the result isn't a real number. It's optional, Call is used if it's nil.
+    Real func(args []float64) (float64, error)
+}
+
+// CheckArity checks whether the function can be called with n arguments.
+func (f *Function) CheckArity(n int) error {
+    if n < f.MinArgs || (f.MaxArgs != Variadic && n > f.MaxArgs) {
+        return fmt.Errorf("%w: %s takes %s, got %d", ErrArity, f.Name, f.Arity(), n)
+    }
+    return nil
+}
+
+// Arity describes the number of arguments the function takes, like '1 argument' or 'at least 1 argument'.
+func (f *Function) Arity() string {
+    switch {
+    case f.MaxArgs == Variadic:
+        return fmt.Sprintf("at least %s", arguments(f.MinArgs))
+    case f.MinArgs == f.MaxArgs:
+        return arguments(f.MinArgs)
+    default:
+        return fmt.Sprintf("%d to %s", f.MinArgs, arguments(f.MaxArgs))
+    }
+}
+
+// arguments returns the count of arguments in words.
+func arguments(n int) string {
+    if n == 1 {
+        return "1 argument"
+    }
+    return fmt.Sprintf("%d arguments", n)
+}
+
+// CallReal calls the function with float64 arguments.
+// Functions without a Real implementation are called with number.Float arguments, the result must be real.
+func (f *Function) CallReal(args []float64) (float64, error) {
+    if f.Real != nil {
+        return f.Real(args)
+    }
+    numbers := make([]number.Number, len(args))
+    for i, arg := range args {
+        numbers[i] = number.Float(arg)
+    }
+    result, err := f.Call(numbers)
+    if err != nil {
+        return 0, err
+    }
+    if c, ok := result.(number.Complex); ok && imag(c) != 0 {
+        return 0, number.ErrNotReal
+    }
+    return result.Float64(), nil
+}
+
+// Lookup returns the built-in function of the given name.
+func Lookup(name string) (*Function, bool) {
+    f, ok := builtins[name]
+    return f, ok
+}
+
+// Names returns the names of all built-in functions in alphabetical order.
+func Names() []string {
+    names := make([]string, 0, len(builtins))
+    for name := range builtins {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
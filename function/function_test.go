@@ -0,0 +1,138 @@
+package function
+
+import (
+    "LexicalCalculator/number"
+    "LexicalCalculator/support"
+    "errors"
+    "math"
+    "math/big"
+    "testing"
+)
+
+func TestFunction_CheckArity(t *testing.T) {
+    testCases := []struct {
+        name  string
+        args  int
+        arity string
+        err   error
+    }{
+        {name: "sqrt", args: 1, arity: "1 argument", err: nil},
+        {name: "sqrt", args: 2, arity: "1 argument", err: ErrArity},
+        {name: "hypot", args: 1, arity: "2 arguments", err: ErrArity},
+        {name: "max", args: 5, arity: "at least 1 argument", err: nil},
+        {name: "min", args: 0, arity: "at least 1 argument", err: ErrArity},
+    }
+
+    for _, tc := range testCases {
+        f, ok := Lookup(tc.name)
+        if !ok {
+            t.Errorf("Error looking up %s: expected a built-in function.\n", tc.name)
+            continue
+        }
+        if f.Arity() != tc.arity {
+            t.Errorf("Error arity of %s: expected %s, got %s.\n", tc.name, tc.arity, f.Arity())
+        }
+        if err := f.CheckArity(tc.args); !errors.Is(err, tc.err) {
+            t.Errorf("Error checking %d arguments of %s: expected error %v, got %v.\n", tc.args, tc.name, tc.err, err)
+        }
+    }
+
+    if _, ok := Lookup("foo"); ok {
+        t.Errorf("Error looking up foo: expected no function.\n")
+    }
+}
+
+func TestBuiltin_Float(t *testing.T) {
+    testCases := []struct {
+        name   string
+        args   []number.Number
+        result float64
+    }{
+        {name: "sqrt", args: []number.Number{number.Float(2)}, result: math.Sqrt2},
+        {name: "cbrt", args: []number.Number{number.Float(-27)}, result: -3},
+        {name: "exp", args: []number.Number{number.Float(1)}, result: math.E},
+        {name: "ln", args: []number.Number{number.Float(math.E)}, result: 1},
+        {name: "log10", args: []number.Number{number.Float(0.01)}, result: -2},
+        {name: "log2", args: []number.Number{number.Float(8)}, result: 3},
+        {name: "sin", args: []number.Number{number.Float(math.Pi / 2)}, result: 1},
+        {name: "cos", args: []number.Number{number.Float(math.Pi)}, result: -1},
+        {name: "tan", args: []number.Number{number.Float(math.Pi / 4)}, result: 1},
+        {name: "asin", args: []number.Number{number.Float(1)}, result: math.Pi / 2},
+        {name: "acos", args: []number.Number{number.Float(1)}, result: 0},
+        {name: "atan", args: []number.Number{number.Float(1)}, result: math.Pi / 4},
+        {name: "sinh", args: []number.Number{number.Float(0)}, result: 0},
+        {name: "cosh", args: []number.Number{number.Float(0)}, result: 1},
+        {name: "tanh", args: []number.Number{number.Float(0)}, result: 0},
+        {name: "asinh", args: []number.Number{number.Float(0)}, result: 0},
+        {name: "acosh", args: []number.Number{number.Float(1)}, result: 0},
+        {name: "atanh", args: []number.Number{number.Float(0)}, result: 0},
+        {name: "floor", args: []number.Number{number.Float(-1.5)}, result: -2},
+        {name: "ceil", args: []number.Number{number.Float(-1.5)}, result: -1},
+        {name: "round", args: []number.Number{number.Float(-1.5)}, result: -2},
+        {name: "trunc", args: []number.Number{number.Float(-1.5)}, result: -1},
+        {name: "abs", args: []number.Number{number.Float(-1.5)}, result: 1.5},
+        {name: "min", args: []number.Number{number.Float(3), number.Float(-1), number.Float(2)}, result: -1},
+        {name: "max", args: []number.Number{number.Float(3), number.Float(-1), number.Float(2)}, result: 3},
+        {name: "hypot", args: []number.Number{number.Float(5), number.Float(12)}, result: 13},
+    }
+
+    for _, tc := range testCases {
+        f, _ := Lookup(tc.name)
+        re, err := f.Call(tc.args)
+        if err != nil {
+            t.Errorf("Error calling %s%v: got error %v.\n", tc.name, tc.args, err)
+            continue
+        }
+        if !support.AlmostEqual(re.Float64(), tc.result, 0.0001) {
+            t.Errorf("Error calling %s%v: expected %f, got %f.\n", tc.name, tc.args, tc.result, re.Float64())
+        }
+    }
+}
+
+func TestBuiltin_Number(t *testing.T) {
+    testCases := []struct {
+        name   string
+        args   []number.Number
+        result string
+        err    error
+    }{
+        // Results that aren't real are promoted to complex numbers.
+        {name: "sqrt", args: []number.Number{number.Float(-4)}, result: "0 + 2i"},
+        {name: "ln", args: []number.Number{number.Complex(1)}, result: "0"},
+        {name: "abs", args: []number.Number{number.Complex(3 + 4i)}, result: "5"},
+        {name: "floor", args: []number.Number{number.Complex(1 + 1i)}, err: number.ErrNotReal},
+        {name: "max", args: []number.Number{number.Complex(1i)}, err: number.ErrNotReal},
+        // Exact backends are rounded exactly.
+        {name: "floor", args: []number.Number{number.NewRat(big.NewRat(-7, 2))}, result: "-4"},
+        {name: "ceil", args: []number.Number{number.NewRat(big.NewRat(-7, 2))}, result: "-3"},
+        {name: "round", args: []number.Number{number.NewRat(big.NewRat(5, 2))}, result: "3"},
+        {name: "trunc", args: []number.Number{number.NewRat(big.NewRat(-7, 2))}, result: "-3"},
+        {name: "abs", args: []number.Number{number.NewRat(big.NewRat(-1, 3))}, result: "1/3"},
+        {name: "max", args: []number.Number{number.Int(2), number.Int(9)}, result: "9"},
+        {name: "floor", args: []number.Number{number.Int(7)}, result: "7"},
+        {name: "round", args: []number.Number{number.NewBigFloat(big.NewFloat(-2.5))}, result: "-3"},
+        // Results are converted to the backend of the argument.
+        {name: "sqrt", args: []number.Number{number.Int(9)}, result: "3"},
+        {name: "sqrt", args: []number.Number{number.Int(2)}, err: number.ErrNotAnInteger},
+        {name: "sqrt", args: []number.Number{number.NewRat(big.NewRat(9, 4))}, result: "1.5"},
+    }
+
+    for _, tc := range testCases {
+        f, _ := Lookup(tc.name)
+        re, err := f.Call(tc.args)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error calling %s%v: expected error %v, got %v.\n", tc.name, tc.args, tc.err, err)
+            continue
+        }
+        if err == nil && re.String() != tc.result {
+            t.Errorf("Error calling %s%v: expected %s, got %s.\n", tc.name, tc.args, tc.result, re)
+        }
+    }
+
+    // Square roots of big floats are calculated in the precision of the argument.
+    f, _ := Lookup("sqrt")
+    re, _ := f.Call([]number.Number{number.NewBigFloat(new(big.Float).SetPrec(200).SetInt64(2))})
+    if re.(number.BigFloat).BigFloat().Prec() != 200 || re.String() == number.Float(math.Sqrt2).String() {
+        t.Errorf("Error calling sqrt with a big float: expected 200 bits of precision, got %s.\n", re)
+    }
+}
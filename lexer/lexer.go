@@ -10,10 +10,26 @@ import (
     "bytes"
     "errors"
     "fmt"
+    "strings"
 )
 
 var ErrInvalidLiteral = errors.New("error token not valid")
 
+// keywords maps the keywords of the calculator to their lexical types, they're never lexed as identifiers.
+var keywords = map[string]string{
+    "calc": token.CALC,
+    "ans":  token.ANS,
+    "mod":  token.PERCENT, // 'mod' is the keyword form of '%'.
+    "rem":  token.REM,
+    "xor":  token.XOR,
+}
+
+// IsKeyword checks whether a name is a keyword, which can't be the name of a variable, a constant or a function.
+func IsKeyword(name string) bool {
+    _, ok := keywords[name]
+    return ok
+}
+
 // Lexer is the lexical analyzer used in the calculator.
 // It's supposed to be called by a parser and will lazily return the next token on the fly.
 // Lexer resets inputBuffer and resets field currPosition and nextPosition after receiving a new input.
@@ -22,6 +38,8 @@ type Lexer struct {
     bufferLength int
     currPosition int
     nextPosition int
+    line         int
+    lineStart    int
     errors       []error
 }
 
@@ -32,6 +50,8 @@ func New() *Lexer {
         bufferLength: 0,
         currPosition: -1,
         nextPosition: 0,
+        line:         1,
+        lineStart:    0,
     }
 }
 
@@ -45,30 +65,28 @@ func (l *Lexer) Input(data string) {
 }
 
 // ReadNextToken returns a token after every read, skipping all encountered white spaces.
+// Every token carries its span in the input, the EOF token has a zero length span at the end of the input.
 func (l *Lexer) ReadNextToken() *token.Token {
     tok := token.New(token.EOF, token.EOF)
 
     if l.nextPosition > l.bufferLength {
+        tok.Span = l.span(l.bufferLength, 0)
         return tok
     }
 
-    next := l.inputBuffer.Next(1)
-    l.currPosition++
-    l.nextPosition++
+    next := l.advance()
 
     // When encounter a white space, advance the pointer.
-    if len(next) > 0 {
-        ok := isWhiteSpace(next[0])
-        for ok {
-            next = l.inputBuffer.Next(1)
-            l.currPosition++
-            l.nextPosition++
-            ok = isWhiteSpace(next[0])
-        }
+    for len(next) > 0 && isWhiteSpace(next[0]) {
+        next = l.advance()
     }
 
+    // The token starts at the current position, the length is known after the entire token is read.
+    start := l.currPosition
+
     switch string(next) {
     case "":
+        tok.Span = l.span(l.bufferLength, 0)
         return tok
     case "'":
         tok = token.New(token.SINGLEQUOTE, string(next))
@@ -89,11 +107,41 @@ func (l *Lexer) ReadNextToken() *token.Token {
     case "-":
         tok = token.New(token.MINUS, string(next))
     case "*":
-        tok = token.New(token.ASTERISK, string(next))
+        // '**' is an alias of '^', it keeps its literal but has the lexical type of a power.
+        if peekedToken, _ := peekBuffer(*l.inputBuffer, 1); peekedToken == '*' {
+            tok = token.New(token.CIRCUMFLEX, string(next)+string(l.advance()))
+        } else {
+            tok = token.New(token.ASTERISK, string(next))
+        }
     case "/":
-        tok = token.New(token.SLASH, string(next))
+        if peekedToken, _ := peekBuffer(*l.inputBuffer, 1); peekedToken == '/' {
+            tok = token.New(token.DBLSLASH, string(next)+string(l.advance()))
+        } else {
+            tok = token.New(token.SLASH, string(next))
+        }
+    case "%":
+        tok = token.New(token.PERCENT, string(next))
+    case "&":
+        tok = token.New(token.AMPERSAND, string(next))
+    case "|":
+        tok = token.New(token.PIPE, string(next))
+    case "~":
+        tok = token.New(token.TILDE, string(next))
+    case "<", ">":
+        // Shifts are the only operators starting with '<' or '>', a single one is unknown.
+        if peekedToken, _ := peekBuffer(*l.inputBuffer, 1); peekedToken == next[0] && next[0] == '<' {
+            tok = token.New(token.SHL, string(next)+string(l.advance()))
+        } else if peekedToken == next[0] {
+            tok = token.New(token.SHR, string(next)+string(l.advance()))
+        } else {
+            tok = token.New(token.UNKNOWN, string(next))
+        }
     case "^":
         tok = token.New(token.CIRCUMFLEX, string(next))
+    case "=":
+        tok = token.New(token.ASSIGN, string(next))
+    case ",":
+        tok = token.New(token.COMMA, string(next))
     default:
         // We handle integers and 'calc' here.
         if isDigit(next[0]) {
@@ -102,6 +150,9 @@ func (l *Lexer) ReadNextToken() *token.Token {
             literal, isInt, err := l.readNumber(next[0])
             if err != nil {
                 tok = token.New(token.UNKNOWN, literal)
+            } else if unit, ok := l.readImaginaryUnit(); ok {
+                // Numbers with an imaginary unit suffix like '4i' or '2.5j' are imaginary numbers.
+                tok = token.New(token.IMAG, literal+unit)
             } else {
                 if isInt {
                     tok = token.New(token.INT, literal)
@@ -111,19 +162,17 @@ func (l *Lexer) ReadNextToken() *token.Token {
             }
         } else if isLetter(next[0]) {
             literal := l.readIdentifier(next[0])
-            switch literal {
-            case "calc":
-                tok = token.New(token.CALC, literal)
-            case "ans":
-                tok = token.New(token.ANS, literal)
-            default:
-                tok = token.New(token.UNKNOWN, literal)
+            if lexicalType, ok := keywords[literal]; ok {
+                tok = token.New(lexicalType, literal)
+            } else {
+                tok = token.New(token.IDENT, literal)
             }
         } else {
             // unknown, append the lexer error.
             tok = token.New(token.UNKNOWN, string(next))
         }
     }
+    tok.Span = l.span(start, l.currPosition-start+1)
     return tok
 }
 
@@ -133,58 +182,125 @@ func (l *Lexer) free() {
     l.bufferLength = 0
     l.currPosition = -1
     l.nextPosition = 0
+    l.line = 1
+    l.lineStart = 0
+}
+
+// advance consumes the next byte in the buffer and moves the pointers forward.
+// It also keeps track of the current line, and the offset where the line starts.
+func (l *Lexer) advance() []byte {
+    next := l.inputBuffer.Next(1)
+    l.currPosition++
+    l.nextPosition++
+    if len(next) > 0 && next[0] == '\n' {
+        l.line++
+        l.lineStart = l.nextPosition
+    }
+    return next
+}
+
+// span returns the span of a token starting at the given offset on the current line.
+func (l *Lexer) span(offset int, length int) token.Span {
+    return token.Span{
+        Offset: offset,
+        Line:   l.line,
+        Column: offset - l.lineStart + 1,
+        Length: length,
+    }
 }
 
-// readNumber returns the integer literal of a number.
-// It advances the pointer until it's at the end of an input or when the next character isn't a digit.
+// readNumber returns the literal of a number, and whether it's an integer.
+// Numbers are decimal, like '12', '0.5' and '6.02e23', or integers with a base prefix, like '0xff', '0b1010' and '0o17'.
+// Digits can be separated by '_', like '1_000_000'.
+// It advances the pointer until it's at the end of an input or when the next character can't be part of the number.
 func (l *Lexer) readNumber(first byte) (literal string, isInt bool, err error) {
-    decimalSeparatorCount := 0
+    numberLiteral := []byte{first}
 
-    numberLiteral := make([]byte, 0)
-    numberLiteral = append(numberLiteral, first)
-    for {
-        peekedToken, _ := peekBuffer(*l.inputBuffer, 1)
-        if isDigit(peekedToken) || isDecimalSeparator(peekedToken) {
-            next := l.inputBuffer.Next(1)
-            l.currPosition++
-            l.nextPosition++
-            numberLiteral = append(numberLiteral, next[0])
-
-            if isDecimalSeparator(peekedToken) {
-                decimalSeparatorCount++
-                peekedToken, _ = peekBuffer(*l.inputBuffer, 1)
-                if !isDigit(peekedToken) {
-                    // When there's no digit after the decimal separator.
-                    err = ErrInvalidLiteral
-                }
-            }
-        } else {
-            break
+    if isDigitOf, ok := basePrefixes[toLower(l.peek(0))]; first == '0' && ok {
+        numberLiteral = append(numberLiteral, l.advance()[0])
+        digits := l.readWhile(func(ch byte) bool { return isDigitOf(ch) || ch == '_' })
+        numberLiteral = append(numberLiteral, digits...)
+        if !isDigitSequence(string(digits), isDigitOf) {
+            return string(numberLiteral), false, ErrInvalidLiteral
         }
+        return string(numberLiteral), true, nil
     }
-    switch {
-    case err != nil, decimalSeparatorCount > 1:
+
+    // The mantissa, with at most one decimal separator, like '12' or '1_000.5'.
+    numberLiteral = append(numberLiteral, l.readWhile(func(ch byte) bool {
+        return isDigit(ch) || isDecimalSeparator(ch) || ch == '_'
+    })...)
+    integer, fraction, hasFraction := strings.Cut(string(numberLiteral), ".")
+    if !isDigitSequence(integer, isDigit) || (hasFraction && !isDigitSequence(fraction, isDigit)) {
         return string(numberLiteral), false, ErrInvalidLiteral
-    case decimalSeparatorCount == 1:
+    }
+
+    // The exponent, like 'e-9' or 'E23'. An 'e' not followed by digits isn't part of the number, like in '2e'.
+    exponent := 0
+    if ch := l.peek(0); ch == 'e' || ch == 'E' {
+        if sign := l.peek(1); isDigit(sign) {
+            exponent = 1
+        } else if (sign == '+' || sign == '-') && isDigit(l.peek(2)) {
+            exponent = 2
+        }
+    }
+    if exponent > 0 {
+        for ; exponent > 0; exponent-- {
+            numberLiteral = append(numberLiteral, l.advance()[0])
+        }
+        digits := l.readWhile(func(ch byte) bool { return isDigit(ch) || ch == '_' })
+        numberLiteral = append(numberLiteral, digits...)
+        if !isDigitSequence(string(digits), isDigit) {
+            return string(numberLiteral), false, ErrInvalidLiteral
+        }
         return string(numberLiteral), false, nil
-    default:
-        return string(numberLiteral), true, nil
     }
+    return string(numberLiteral), !hasFraction, nil
+}
+
+// readWhile advances the pointer as long as the next character matches, and returns the characters read.
+func (l *Lexer) readWhile(matches func(byte) bool) []byte {
+    read := make([]byte, 0)
+    for l.inputBuffer.Len() > 0 && matches(l.peek(0)) {
+        read = append(read, l.advance()[0])
+    }
+    return read
+}
+
+// peek returns the character n characters after the next one without consuming anything, 0 past the end of the input.
+func (l *Lexer) peek(n int) byte {
+    if l.inputBuffer.Len() <= n {
+        return 0
+    }
+    return l.inputBuffer.Bytes()[n]
+}
+
+// readImaginaryUnit reads the imaginary unit 'i' or 'j' right after a number.
+// The unit must not be followed by another letter, since it's the start of an identifier then.
+func (l *Lexer) readImaginaryUnit() (string, bool) {
+    peekedToken, _ := peekBuffer(*l.inputBuffer, 1)
+    if !isImaginaryUnit(peekedToken) {
+        return "", false
+    }
+    if l.inputBuffer.Len() > 1 && isLetter(l.inputBuffer.Bytes()[1]) {
+        return "", false
+    }
+    next := l.advance()
+    return string(next), true
 }
 
-// readNumber returns the literal of an identifier.
-// It advances the pointer until it's at the end of an input or when the next character isn't a letter.
+// readIdentifier returns the literal of an identifier.
+// It advances the pointer until it's at the end of an input or when the next character isn't a letter or a digit.
+// Identifiers start with a letter, the following characters can also be digits, like 'rate2'.
 func (l *Lexer) readIdentifier(first byte) string {
     identifier := make([]byte, 0)
     identifier = append(identifier, first)
 
     for {
         peekedToken, _ := peekBuffer(*l.inputBuffer, 1)
-        if isLetter(peekedToken) {
-            next := l.inputBuffer.Next(1)
+        if isLetter(peekedToken) || isDigit(peekedToken) {
+            next := l.advance()
             identifier = append(identifier, next[0])
-            l.currPosition++
-            l.nextPosition++
         } else {
             break
         }
@@ -225,6 +341,45 @@ func isDigit(ch byte) bool {
     return false
 }
 
+// basePrefixes maps the letters of the base prefixes of integer literals, like the 'x' of '0x', to the digits of their base.
+var basePrefixes = map[byte]func(byte) bool{'x': isHexDigit, 'b': isBinaryDigit, 'o': isOctalDigit}
+
+// isHexDigit determines whether an input character is a hexadecimal digit.
+func isHexDigit(ch byte) bool {
+    return isDigit(ch) || ('a' <= toLower(ch) && toLower(ch) <= 'f')
+}
+
+// isOctalDigit determines whether an input character is an octal digit.
+func isOctalDigit(ch byte) bool {
+    return '0' <= ch && ch <= '7'
+}
+
+// isBinaryDigit determines whether an input character is a binary digit.
+func isBinaryDigit(ch byte) bool {
+    return ch == '0' || ch == '1'
+}
+
+// isDigitSequence determines whether a string is a non-empty sequence of digits, where single '_' can separate two digits.
+func isDigitSequence(s string, isDigitOf func(byte) bool) bool {
+    if s == "" || s[0] == '_' || s[len(s)-1] == '_' || strings.Contains(s, "__") {
+        return false
+    }
+    for i := 0; i < len(s); i++ {
+        if !isDigitOf(s[i]) && s[i] != '_' {
+            return false
+        }
+    }
+    return true
+}
+
+// toLower returns the lower case of an ASCII letter, other characters are returned as they are.
+func toLower(ch byte) byte {
+    if 'A' <= ch && ch <= 'Z' {
+        return ch + 'a' - 'A'
+    }
+    return ch
+}
+
 // isLetter determines whether an input character is a letter.
 func isLetter(ch byte) bool {
     // For ASCII, letter a-z lies within [97, 122] and A-Z within [65, 90].
@@ -235,6 +390,11 @@ func isLetter(ch byte) bool {
     return false
 }
 
+// isImaginaryUnit determines whether an input character is the imaginary unit 'i' or 'j'.
+func isImaginaryUnit(ch byte) bool {
+    return ch == 'i' || ch == 'j'
+}
+
 // isLetter determines whether an input character is a decimal point.
 func isDecimalSeparator(ch byte) bool {
     return ch == 46
@@ -31,7 +31,7 @@ func TestLexer_ReadNextToken(t *testing.T) {
             },
         },
         {
-            input: "+-*/'()[]{}^",
+            input: "+-*/'()[]{}^=",
             result: []token.Token{
                 {Literal: "+", LexicalType: token.PLUS},
                 {Literal: "-", LexicalType: token.MINUS},
@@ -45,6 +45,7 @@ func TestLexer_ReadNextToken(t *testing.T) {
                 {Literal: "{", LexicalType: token.LCURBRACK},
                 {Literal: "}", LexicalType: token.RCURBRACK},
                 {Literal: "^", LexicalType: token.CIRCUMFLEX},
+                {Literal: "=", LexicalType: token.ASSIGN},
                 {Literal: token.EOF, LexicalType: token.EOF},
             },
         },
@@ -60,6 +61,99 @@ func TestLexer_ReadNextToken(t *testing.T) {
                 {Literal: token.EOF, LexicalType: token.EOF},
             },
         },
+        {
+            input: "2**3 * *^***",
+            result: []token.Token{
+                {Literal: "2", LexicalType: token.INT},
+                {Literal: "**", LexicalType: token.CIRCUMFLEX},
+                {Literal: "3", LexicalType: token.INT},
+                {Literal: "*", LexicalType: token.ASTERISK},
+                {Literal: "*", LexicalType: token.ASTERISK},
+                {Literal: "^", LexicalType: token.CIRCUMFLEX},
+                {Literal: "**", LexicalType: token.CIRCUMFLEX},
+                {Literal: "*", LexicalType: token.ASTERISK},
+                {Literal: token.EOF, LexicalType: token.EOF},
+            },
+        },
+        {
+            input: "7//2%3 mod rem / /",
+            result: []token.Token{
+                {Literal: "7", LexicalType: token.INT},
+                {Literal: "//", LexicalType: token.DBLSLASH},
+                {Literal: "2", LexicalType: token.INT},
+                {Literal: "%", LexicalType: token.PERCENT},
+                {Literal: "3", LexicalType: token.INT},
+                {Literal: "mod", LexicalType: token.PERCENT},
+                {Literal: "rem", LexicalType: token.REM},
+                {Literal: "/", LexicalType: token.SLASH},
+                {Literal: "/", LexicalType: token.SLASH},
+                {Literal: token.EOF, LexicalType: token.EOF},
+            },
+        },
+        {
+            input: "~x&1|y xor 2<<3>>4 < >",
+            result: []token.Token{
+                {Literal: "~", LexicalType: token.TILDE},
+                {Literal: "x", LexicalType: token.IDENT},
+                {Literal: "&", LexicalType: token.AMPERSAND},
+                {Literal: "1", LexicalType: token.INT},
+                {Literal: "|", LexicalType: token.PIPE},
+                {Literal: "y", LexicalType: token.IDENT},
+                {Literal: "xor", LexicalType: token.XOR},
+                {Literal: "2", LexicalType: token.INT},
+                {Literal: "<<", LexicalType: token.SHL},
+                {Literal: "3", LexicalType: token.INT},
+                {Literal: ">>", LexicalType: token.SHR},
+                {Literal: "4", LexicalType: token.INT},
+                {Literal: "<", LexicalType: token.UNKNOWN},
+                {Literal: ">", LexicalType: token.UNKNOWN},
+                {Literal: token.EOF, LexicalType: token.EOF},
+            },
+        },
+        {
+            input: "0xFF 0b1010 0o17 0X1f 017 1_000_000 0xdead_beef",
+            result: []token.Token{
+                {Literal: "0xFF", LexicalType: token.INT},
+                {Literal: "0b1010", LexicalType: token.INT},
+                {Literal: "0o17", LexicalType: token.INT},
+                {Literal: "0X1f", LexicalType: token.INT},
+                {Literal: "017", LexicalType: token.INT},
+                {Literal: "1_000_000", LexicalType: token.INT},
+                {Literal: "0xdead_beef", LexicalType: token.INT},
+                {Literal: token.EOF, LexicalType: token.EOF},
+            },
+        },
+        {
+            input: "1e-9 6.02E23 1_000.000_1e+1_0 2e 3e+ 1e3i",
+            result: []token.Token{
+                {Literal: "1e-9", LexicalType: token.FLOAT},
+                {Literal: "6.02E23", LexicalType: token.FLOAT},
+                {Literal: "1_000.000_1e+1_0", LexicalType: token.FLOAT},
+                {Literal: "2", LexicalType: token.INT},
+                {Literal: "e", LexicalType: token.IDENT},
+                {Literal: "3", LexicalType: token.INT},
+                {Literal: "e", LexicalType: token.IDENT},
+                {Literal: "+", LexicalType: token.PLUS},
+                {Literal: "1e3i", LexicalType: token.IMAG},
+                {Literal: token.EOF, LexicalType: token.EOF},
+            },
+        },
+        {
+            input: "0x 0b102 0o8 1__0 1_ 1_.5 1._5 1e5_",
+            result: []token.Token{
+                {Literal: "0x", LexicalType: token.UNKNOWN},
+                {Literal: "0b10", LexicalType: token.INT},
+                {Literal: "2", LexicalType: token.INT},
+                {Literal: "0o", LexicalType: token.UNKNOWN},
+                {Literal: "8", LexicalType: token.INT},
+                {Literal: "1__0", LexicalType: token.UNKNOWN},
+                {Literal: "1_", LexicalType: token.UNKNOWN},
+                {Literal: "1_.5", LexicalType: token.UNKNOWN},
+                {Literal: "1._5", LexicalType: token.UNKNOWN},
+                {Literal: "1e5_", LexicalType: token.UNKNOWN},
+                {Literal: token.EOF, LexicalType: token.EOF},
+            },
+        },
         {
             input: "368000 12345",
             result: []token.Token{
@@ -76,12 +170,48 @@ func TestLexer_ReadNextToken(t *testing.T) {
                 {Literal: token.EOF, LexicalType: token.EOF},
             },
         },
+        {
+            input: "4i 2.5j 3 in 7ix",
+            result: []token.Token{
+                {Literal: "4i", LexicalType: token.IMAG},
+                {Literal: "2.5j", LexicalType: token.IMAG},
+                {Literal: "3", LexicalType: token.INT},
+                {Literal: "in", LexicalType: token.IDENT},
+                {Literal: "7", LexicalType: token.INT},
+                {Literal: "ix", LexicalType: token.IDENT},
+                {Literal: token.EOF, LexicalType: token.EOF},
+            },
+        },
         {
             input: "calc ans hello",
             result: []token.Token{
                 {Literal: "calc", LexicalType: token.CALC},
                 {Literal: "ans", LexicalType: token.ANS},
-                {Literal: "hello", LexicalType: token.UNKNOWN},
+                {Literal: "hello", LexicalType: token.IDENT},
+                {Literal: token.EOF, LexicalType: token.EOF},
+            },
+        },
+        {
+            input: "rate = 0.07 rate_2 = rate2",
+            result: []token.Token{
+                {Literal: "rate", LexicalType: token.IDENT},
+                {Literal: "=", LexicalType: token.ASSIGN},
+                {Literal: "0.07", LexicalType: token.FLOAT},
+                {Literal: "rate_2", LexicalType: token.IDENT},
+                {Literal: "=", LexicalType: token.ASSIGN},
+                {Literal: "rate2", LexicalType: token.IDENT},
+                {Literal: token.EOF, LexicalType: token.EOF},
+            },
+        },
+        {
+            input: "max(1, 2.5)",
+            result: []token.Token{
+                {Literal: "max", LexicalType: token.IDENT},
+                {Literal: "(", LexicalType: token.LPAREN},
+                {Literal: "1", LexicalType: token.INT},
+                {Literal: ",", LexicalType: token.COMMA},
+                {Literal: "2.5", LexicalType: token.FLOAT},
+                {Literal: ")", LexicalType: token.RPAREN},
                 {Literal: token.EOF, LexicalType: token.EOF},
             },
         },
@@ -183,3 +313,89 @@ func TestLexer_ReadNextToken(t *testing.T) {
         }
     }
 }
+
+func TestIsKeyword(t *testing.T) {
+    testCases := []struct {
+        name    string
+        keyword bool
+    }{
+        {name: "calc", keyword: true},
+        {name: "ans", keyword: true},
+        {name: "mod", keyword: true},
+        {name: "rem", keyword: true},
+        {name: "xor", keyword: true},
+        {name: "answer", keyword: false},
+        {name: "x", keyword: false},
+        {name: "", keyword: false},
+    }
+
+    for _, tc := range testCases {
+        if IsKeyword(tc.name) != tc.keyword {
+            t.Errorf("Error checking keyword %q: expected %t, got %t.\n", tc.name, tc.keyword, !tc.keyword)
+        }
+    }
+}
+
+func TestLexer_ReadNextToken_Span(t *testing.T) {
+    l := New()
+    testCases := []struct {
+        input string
+        spans []token.Span
+    }{
+        {
+            input: "",
+            spans: []token.Span{
+                {Offset: 0, Line: 1, Column: 1, Length: 0},
+            },
+        },
+        {
+            input: "calc '12.5 + ans'",
+            spans: []token.Span{
+                {Offset: 0, Line: 1, Column: 1, Length: 4},
+                {Offset: 5, Line: 1, Column: 6, Length: 1},
+                {Offset: 6, Line: 1, Column: 7, Length: 4},
+                {Offset: 11, Line: 1, Column: 12, Length: 1},
+                {Offset: 13, Line: 1, Column: 14, Length: 3},
+                {Offset: 16, Line: 1, Column: 17, Length: 1},
+                {Offset: 17, Line: 1, Column: 18, Length: 0},
+            },
+        },
+        {
+            input: "1 +\n  22\n",
+            spans: []token.Span{
+                {Offset: 0, Line: 1, Column: 1, Length: 1},
+                {Offset: 2, Line: 1, Column: 3, Length: 1},
+                {Offset: 6, Line: 2, Column: 3, Length: 2},
+                {Offset: 9, Line: 3, Column: 1, Length: 0},
+            },
+        },
+        {
+            input: "0xff+1e-9",
+            spans: []token.Span{
+                {Offset: 0, Line: 1, Column: 1, Length: 4},
+                {Offset: 4, Line: 1, Column: 5, Length: 1},
+                {Offset: 5, Line: 1, Column: 6, Length: 4},
+                {Offset: 9, Line: 1, Column: 10, Length: 0},
+            },
+        },
+        {
+            input: "2 ** 3",
+            spans: []token.Span{
+                {Offset: 0, Line: 1, Column: 1, Length: 1},
+                {Offset: 2, Line: 1, Column: 3, Length: 2},
+                {Offset: 5, Line: 1, Column: 6, Length: 1},
+                {Offset: 6, Line: 1, Column: 7, Length: 0},
+            },
+        },
+    }
+
+    for _, tc := range testCases {
+        l.Input(tc.input)
+        for _, expectedSpan := range tc.spans {
+            tok := l.ReadNextToken()
+            if tok.Span != expectedSpan {
+                t.Errorf("Error token span of %s in %q: expected %+v, got %+v.\n", tok.Literal, tc.input, expectedSpan, tok.Span)
+            }
+        }
+    }
+}
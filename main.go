@@ -1,28 +1,63 @@
 package main
 
 import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/calculus"
+    "LexicalCalculator/format"
+    "LexicalCalculator/function"
     "LexicalCalculator/lexer"
+    "LexicalCalculator/number"
     "LexicalCalculator/parser"
+    "LexicalCalculator/token"
     "bufio"
+    "errors"
+    "flag"
     "fmt"
     "os"
+    "strconv"
     "strings"
 )
 
 const (
-    REPL  = ">> "
-    QUIT  = "quit"
-    HELP  = "help"
-    CLEAR = "clear"
+    REPL   = ">> "
+    QUIT   = "quit"
+    HELP   = "help"
+    CLEAR  = "clear"
+    MODE   = "mode"
+    DIFF   = "diff"
+    RENDER = "render"
+    TREE   = "tree"
+
+    FORMAT    = "format"
+    PRECISION = "precision"
+    SEPARATOR = "separator"
+    ROUNDING  = "rounding"
+
+    PROGRAMMER = "programmer"
+
+    INCORRECT = "Incorrect prompt: "
 )
 
 func main() {
+    o, err := parseFlags(os.Args[1:], os.Stderr)
+    if errors.Is(err, flag.ErrHelp) {
+        return
+    }
+    if err != nil {
+        os.Exit(exitUsage)
+    }
+
     // Initialize lexer and parser.
     l := lexer.New()
     p := parser.New(l)
+    if o.hasEquation {
+        os.Exit(evaluate(p, o, os.Stdout, os.Stderr))
+    }
+    // The format results are printed in, changed with the format command.
+    resultFormat := o.format
 
     // We want a calculator that reads scripts like `calc "1 + 1"`, `calc "2 * 3 + 4"` or `quit`.
-    // Operators: +, -, *, /, **, (), [], {}.
+    // Operators: +, -, *, /, **, %, //, &, |, xor, <<, >>, ~, (), [], {}.
     // Should have prefix calculation like `calc "-5 + 4"`
     // Should handle error like zero-divisions.
     // Create CLI environment(repl), takes commands; ['calc', 'end'].
@@ -33,13 +68,34 @@ func main() {
     scanner := bufio.NewScanner(os.Stdin)
     for {
         fmt.Printf("%sInsert your prompt: ", REPL)
-        scanner.Scan()
+        // The end of the input quits, like the end of a script piped into the calculator.
+        if !scanner.Scan() {
+            fmt.Println()
+            if err := scanner.Err(); err != nil {
+                fmt.Fprintf(os.Stderr, "calculator: %s\n", err)
+                os.Exit(exitError)
+            }
+            return
+        }
         cmd := scanner.Text()
+        fields := strings.Fields(strings.ToLower(cmd))
 
         switch strings.ToLower(cmd) {
         case HELP:
             fmt.Println("Input prompts")
             fmt.Println("    - calc '<equation>'")
+            fmt.Println("    - calc '<variable> = <equation>'")
+            fmt.Println("    - functions: " + strings.Join(function.Names(), ", "))
+            fmt.Println("    - diff '<equation>' <variable>")
+            fmt.Println("    - render [latex | mathml] '<equation>'")
+            fmt.Println("    - tree [ascii | dot] '<equation>'")
+            fmt.Println("    - mode [float | rational | bigfloat [<precision in bits>] | int | complex | programmer]")
+            fmt.Println("      int and programmer are int64: literals and results go up to 0x7fffffffffffffff, bit 63 is only set by negative numbers like ~0")
+            fmt.Println("    - format [default | auto | fixed | significant | scientific | engineering | hex | binary | octal | fraction] [<digits>]")
+            fmt.Println("    - format separator [<separator>]")
+            fmt.Println("    - format rounding [half-even | half-up | toward-zero | floor | ceiling]")
+            fmt.Println("    - precision [<digits>]")
+            fmt.Println("    - clear")
             fmt.Println("    - quit")
             fmt.Println("    - help")
         case CLEAR:
@@ -49,13 +105,301 @@ func main() {
             fmt.Println("Exit Calculator.")
             return
         default:
+            if len(fields) > 0 && fields[0] == MODE {
+                setMode(p, fields[1:])
+                continue
+            }
+            if len(fields) > 0 && fields[0] == FORMAT {
+                setFormat(&resultFormat, fields[1:])
+                continue
+            }
+            if len(fields) > 0 && fields[0] == PRECISION {
+                setPrecision(&resultFormat, fields[1:])
+                continue
+            }
+            if len(fields) > 0 && fields[0] == DIFF {
+                differentiate(p, cmd)
+                continue
+            }
+            if len(fields) > 0 && fields[0] == RENDER {
+                render(p, cmd)
+                continue
+            }
+            if len(fields) > 0 && fields[0] == TREE {
+                drawTree(p, cmd)
+                continue
+            }
+
             calculatedResult, err := p.Evaluate(cmd)
             if err != nil {
-                fmt.Printf("%sIncorrect prompt: %s\n", REPL, cmd)
+                printError(cmd, 0, err)
+                continue
+            }
+            result, err := formatResult(calculatedResult, p.Backend(), resultFormat)
+            if err != nil {
+                printError(cmd, 0, err)
                 continue
             }
-            // Round the result to 4 decimal places
-            fmt.Printf(">> result: %.4f\n", calculatedResult)
+            fmt.Printf(">> result: %s\n", result)
+        }
+    }
+}
+
+// printError prints an incorrect prompt and its error.
+// Every error found in the prompt is marked with a caret under the offending token,
+// the offset is where the spans of the errors start in the prompt.
+func printError(cmd string, offset int, err error) {
+    fmt.Printf("%s%s%s\n", REPL, INCORRECT, cmd)
+    var errList parser.ErrorList
+    if errors.As(err, &errList) {
+        for _, parseErr := range errList {
+            fmt.Printf("%s %s\n", caret(REPL+INCORRECT+strings.Repeat(" ", offset), parseErr.Span), parseErr.Message)
+        }
+    } else {
+        fmt.Printf("%s%s\n", REPL, err)
+    }
+}
+
+// differentiate prints the derivative of an equation, like `diff 'x ^ 2 + y' x`.
+func differentiate(p *parser.Parser, cmd string) {
+    c, ok := splitCommand(cmd)
+    if !ok || len(c.after) != 1 {
+        fmt.Printf("%s%s%s\n", REPL, INCORRECT, cmd)
+        fmt.Printf("%sexpected diff '<equation>' <variable>\n", REPL)
+        return
+    }
+
+    n, err := p.ParseEquation(c.equation)
+    if err != nil {
+        printError(cmd, c.offset, err)
+        return
+    }
+    variable := c.after[0]
+    derivative, err := calculus.Derive(n, variable)
+    if err != nil {
+        printError(cmd, 0, err)
+        return
+    }
+    fmt.Printf("%sd/d%s: %s\n", REPL, variable, parser.Format(derivative))
+}
+
+// render prints an equation in LaTeX or MathML, like `render latex '1 / x'`.
+func render(p *parser.Parser, cmd string) {
+    renderers := map[string]func(ast.Node) string{
+        "latex":  parser.LaTeX,
+        "mathml": parser.MathML,
+    }
+
+    c, ok := splitCommand(cmd)
+    if !ok || len(c.after) != 0 || len(c.before) != 2 || renderers[strings.ToLower(c.before[1])] == nil {
+        fmt.Printf("%s%s%s\n", REPL, INCORRECT, cmd)
+        fmt.Printf("%sexpected render [latex | mathml] '<equation>'\n", REPL)
+        return
+    }
+
+    n, err := p.ParseEquation(c.equation)
+    if err != nil {
+        printError(cmd, c.offset, err)
+        return
+    }
+    language := strings.ToLower(c.before[1])
+    fmt.Printf("%s%s: %s\n", REPL, language, renderers[language](n))
+}
+
+// drawTree prints the tree of an equation as ASCII art or Graphviz DOT, like `tree '-1 ^ 4'` or `tree dot '-1 ^ 4'`.
+func drawTree(p *parser.Parser, cmd string) {
+    drawers := map[string]func(ast.Node) string{
+        "ascii": parser.Tree,
+        "dot":   parser.DOT,
+    }
+
+    c, ok := splitCommand(cmd)
+    if ok && len(c.before) == 1 {
+        c.before = append(c.before, "ascii")
+    }
+    if !ok || len(c.after) != 0 || len(c.before) != 2 || drawers[strings.ToLower(c.before[1])] == nil {
+        fmt.Printf("%s%s%s\n", REPL, INCORRECT, cmd)
+        fmt.Printf("%sexpected tree [ascii | dot] '<equation>'\n", REPL)
+        return
+    }
+
+    n, err := p.ParseEquation(c.equation)
+    if err != nil {
+        printError(cmd, c.offset, err)
+        return
+    }
+    fmt.Print(drawers[strings.ToLower(c.before[1])](n))
+}
+
+// setMode sets the numeric backend of the parser, like `mode rational` or `mode bigfloat 512`.
+// Without arguments, it prints the current backend.
+func setMode(p *parser.Parser, args []string) {
+    if len(args) == 0 {
+        fmt.Printf("%smode: %s\n", REPL, p.Backend())
+        return
+    }
+
+    var precision uint64
+    if len(args) > 1 {
+        var err error
+        precision, err = strconv.ParseUint(args[1], 10, 32)
+        if err != nil || precision == 0 {
+            fmt.Printf("%sIncorrect precision: %s\n", REPL, args[1])
+            return
+        }
+    }
+
+    var backend number.Backend = programmerBackend{number.IntBackend}
+    var err error
+    if args[0] != PROGRAMMER {
+        backend, err = number.NewBackend(args[0], uint(precision))
+    }
+    if err != nil {
+        fmt.Printf("%sIncorrect mode: %s\n", REPL, args[0])
+        return
+    }
+    p.SetBackend(backend)
+    fmt.Printf("%smode: %s\n", REPL, backend)
+}
+
+// setFormat sets the format results are printed in, like `format engineering 3`, `format hex`, `format separator ,` or `format rounding floor`.
+// The separator and the rounding mode are kept when the notation changes, `format separator` without a separator stops grouping digits,
+// and `format rounding` without a rounding mode rounds half to even again.
+// Without arguments, it prints the current format.
+func setFormat(f *format.Format, args []string) {
+    if len(args) == 0 {
+        fmt.Printf("%sformat: %s\n", REPL, f)
+        return
+    }
+
+    if args[0] == SEPARATOR {
+        if len(args) > 2 {
+            fmt.Printf("%sIncorrect separator: %s\n", REPL, strings.Join(args[1:], " "))
+            return
+        }
+        f.Separator = ""
+        if len(args) == 2 {
+            f.Separator = args[1]
+        }
+        fmt.Printf("%sformat: %s\n", REPL, f)
+        return
+    }
+
+    if args[0] == ROUNDING {
+        rounding := ""
+        if len(args) > 1 {
+            rounding = strings.Join(args[1:], " ")
+        }
+        if err := f.SetRounding(rounding); err != nil {
+            fmt.Printf("%sIncorrect rounding: %s\n", REPL, rounding)
+            return
+        }
+        fmt.Printf("%sformat: %s\n", REPL, f)
+        return
+    }
+
+    digits := -1
+    if len(args) > 1 {
+        var ok bool
+        digits, ok = parseDigits(args[1:])
+        if !ok {
+            return
         }
     }
+    newFormat, err := format.New(args[0], digits)
+    if err != nil {
+        fmt.Printf("%sIncorrect format: %s\n", REPL, args[0])
+        return
+    }
+    newFormat.Separator = f.Separator
+    newFormat.Rounding = f.Rounding
+    *f = newFormat
+    fmt.Printf("%sformat: %s\n", REPL, f)
+}
+
+// setPrecision sets the digits of the format results are printed in, like `precision 8`.
+// The digits are decimal places or significant digits depending on the notation, the default notation trims trailing zeros with a precision.
+// Without arguments, it prints the current format.
+func setPrecision(f *format.Format, args []string) {
+    if len(args) == 0 {
+        fmt.Printf("%sformat: %s\n", REPL, f)
+        return
+    }
+
+    digits, ok := parseDigits(args)
+    if !ok {
+        return
+    }
+    *f = f.WithPrecision(digits)
+    fmt.Printf("%sformat: %s\n", REPL, f)
+}
+
+// parseDigits parses the digits of a format, printing the arguments if they aren't a single number from 0 to format.MaxDigits.
+func parseDigits(args []string) (int, bool) {
+    digits, err := strconv.Atoi(args[0])
+    if err != nil || digits < 0 || digits > format.MaxDigits || len(args) > 1 {
+        fmt.Printf("%sIncorrect digits: %s\n", REPL, strings.Join(args, " "))
+        return 0, false
+    }
+    return digits, true
+}
+
+// programmerBackend is the int backend of the programmer mode, where results are also printed in hexadecimal.
+type programmerBackend struct {
+    number.Backend
+}
+
+// String returns the name of the mode.
+func (programmerBackend) String() string {
+    return PROGRAMMER
+}
+
+// formatResult returns the text of a calculated result in a format.
+// In the default format, float results are rounded to 4 decimal places, complex results are formatted as a + bi with both parts rounded the same way,
+// and results of the arbitrary-precision backends are printed in full. Results of the programmer mode are also printed as 64-bit hexadecimal.
+func formatResult(n number.Number, backend number.Backend, f format.Format) (string, error) {
+    text, err := f.Text(n)
+    if err != nil {
+        return "", err
+    }
+    if i, ok := n.(number.Int); ok && (f.Notation == "" || f.Notation == format.DEFAULT || f.Notation == format.AUTO) {
+        if _, ok := backend.(programmerBackend); ok {
+            // Negative results are shown in two's complement, like in a register.
+            return fmt.Sprintf("%s (%#x)", text, uint64(i)), nil
+        }
+    }
+    return text, nil
+}
+
+// caret returns a line that marks the span with carets, aligned with the input printed after the prefix.
+func caret(prefix string, span token.Span) string {
+    length := span.Length
+    if length < 1 {
+        // Zero length spans like the end of the input still need a caret to be visible.
+        length = 1
+    }
+    return strings.Repeat(" ", len(prefix)+span.Column-1) + strings.Repeat("^", length)
+}
+
+// command is a command with a quoted equation, like `diff '<equation>' <variable>`.
+type command struct {
+    before   []string // The words before the equation, starting with the name of the command.
+    equation string
+    offset   int // Where the equation starts in the command.
+    after    []string
+}
+
+// splitCommand splits a command around its quoted equation, the equation is between the first and the last quote.
+func splitCommand(cmd string) (command, bool) {
+    opening := strings.Index(cmd, "'")
+    closing := strings.LastIndex(cmd, "'")
+    if opening < 0 || closing == opening {
+        return command{}, false
+    }
+    return command{
+        before:   strings.Fields(cmd[:opening]),
+        equation: cmd[opening+1 : closing],
+        offset:   opening + 1,
+        after:    strings.Fields(cmd[closing+1:]),
+    }, true
 }
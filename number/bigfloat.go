@@ -0,0 +1,283 @@
+package number
+
+import (
+    "fmt"
+    "math"
+    "math/big"
+)
+
+// bigFloatBackend evaluates equations with big.Float of a fixed precision.
+type bigFloatBackend struct {
+    precision uint
+}
+
+// NewBigFloatBackend creates a backend evaluating equations with big.Float of the given precision in bits.
+func NewBigFloatBackend(precision uint) Backend {
+    return bigFloatBackend{precision: precision}
+}
+
+// Parse converts a literal into a BigFloat, rounded to the precision of the backend.
+func (b bigFloatBackend) Parse(literal string) (Number, error) {
+    f, _, err := big.ParseFloat(literal, 10, b.precision, big.ToNearestEven)
+    if err != nil {
+        return nil, ErrInvalidLiteral
+    }
+    return BigFloat{f: f}, nil
+}
+
+// Convert converts a Number into a BigFloat.
+func (b bigFloatBackend) Convert(n Number) (Number, error) {
+    f, err := toBigFloat(n, b.precision)
+    if err != nil {
+        return nil, err
+    }
+    return BigFloat{f: f}, nil
+}
+
+// String returns the name of the backend.
+func (b bigFloatBackend) String() string {
+    return BIGFLOAT
+}
+
+// BigFloat is a Number backed by big.Float.
+// Results of operations have the precision of the receiver.
+type BigFloat struct {
+    f *big.Float
+}
+
+// NewBigFloat creates a BigFloat holding a copy of f.
+func NewBigFloat(f *big.Float) BigFloat {
+    return BigFloat{f: new(big.Float).Copy(f)}
+}
+
+// BigFloat returns a copy of the underlying big.Float.
+func (b BigFloat) BigFloat() *big.Float {
+    return new(big.Float).Copy(b.f)
+}
+
+// Add returns b + n.
+func (b BigFloat) Add(n Number) (Number, error) {
+    other, err := b.operand(n)
+    if err != nil {
+        return nil, err
+    }
+    return finite(b.result().Add(b.f, other))
+}
+
+// Sub returns b - n.
+func (b BigFloat) Sub(n Number) (Number, error) {
+    other, err := b.operand(n)
+    if err != nil {
+        return nil, err
+    }
+    return finite(b.result().Sub(b.f, other))
+}
+
+// Mul returns b * n.
+func (b BigFloat) Mul(n Number) (Number, error) {
+    other, err := b.operand(n)
+    if err != nil {
+        return nil, err
+    }
+    return finite(b.result().Mul(b.f, other))
+}
+
+// Quo returns b / n.
+func (b BigFloat) Quo(n Number) (Number, error) {
+    other, err := b.operand(n)
+    if err != nil {
+        return nil, err
+    }
+    if other.Sign() == 0 {
+        return nil, ErrZeroDivision
+    }
+    return finite(b.result().Quo(b.f, other))
+}
+
+// Pow returns b ^ n.
+// Integer exponents are calculated in full precision. Other exponents are calculated in float64.
+// Results beyond the exponent range of big.Float are errors.
+func (b BigFloat) Pow(n Number) (Number, error) {
+    exponent, err := b.operand(n)
+    if err != nil {
+        return nil, err
+    }
+
+    if !exponent.IsInt() {
+        f := math.Pow(b.Float64(), n.Float64())
+        if math.IsNaN(f) || math.IsInf(f, 0) {
+            return nil, ErrNotANumber
+        }
+        return BigFloat{f: b.result().SetFloat64(f)}, nil
+    }
+
+    k, accuracy := exponent.Int64()
+    // -k overflows for the smallest int64.
+    if accuracy != big.Exact || k == math.MinInt64 {
+        return nil, ErrExponentTooLarge
+    }
+    if k < 0 && b.f.Sign() == 0 {
+        return nil, ErrZeroDivision
+    }
+
+    negative := k < 0
+    if negative {
+        k = -k
+    }
+
+    // Exponentiation by squaring.
+    result := b.result().SetInt64(1)
+    square := b.result().Set(b.f)
+    for ; k > 0; k >>= 1 {
+        if k&1 == 1 {
+            result.Mul(result, square)
+        }
+        square.Mul(square, square)
+    }
+    if negative {
+        result.Quo(b.result().SetInt64(1), result)
+    }
+    if result.IsInf() {
+        return nil, ErrExponentTooLarge
+    }
+    return BigFloat{f: result}, nil
+}
+
+// FloorQuo returns ⌊b / n⌋.
+func (b BigFloat) FloorQuo(n Number) (Number, error) {
+    other, err := b.operand(n)
+    if err != nil {
+        return nil, err
+    }
+    if other.Sign() == 0 {
+        return nil, ErrZeroDivision
+    }
+    return finite(floorBigFloat(b.result().Quo(b.f, other)))
+}
+
+// Mod returns b mod n = b - n * ⌊b / n⌋, which has the sign of n.
+func (b BigFloat) Mod(n Number) (Number, error) {
+    other, err := b.operand(n)
+    if err != nil {
+        return nil, err
+    }
+    if other.Sign() == 0 {
+        return nil, ErrZeroDivision
+    }
+    q := floorBigFloat(b.result().Quo(b.f, other))
+    return finite(q.Sub(b.f, q.Mul(q, other)))
+}
+
+// Rem returns b rem n = b - n * trunc(b / n), which has the sign of b.
+func (b BigFloat) Rem(n Number) (Number, error) {
+    other, err := b.operand(n)
+    if err != nil {
+        return nil, err
+    }
+    if other.Sign() == 0 {
+        return nil, ErrZeroDivision
+    }
+    q := truncBigFloat(b.result().Quo(b.f, other))
+    return finite(q.Sub(b.f, q.Mul(q, other)))
+}
+
+// Neg returns -b.
+func (b BigFloat) Neg() (Number, error) {
+    return BigFloat{f: b.result().Neg(b.f)}, nil
+}
+
+// Cmp compares b and n.
+func (b BigFloat) Cmp(n Number) (int, error) {
+    other, err := toBigFloat(n, b.f.Prec())
+    if err != nil {
+        return 0, err
+    }
+    return b.f.Cmp(other), nil
+}
+
+// IsZero reports whether b is 0.
+func (b BigFloat) IsZero() bool {
+    return b.f.Sign() == 0
+}
+
+// Float64 returns the nearest float64 of b.
+func (b BigFloat) Float64() float64 {
+    f, _ := b.f.Float64()
+    return f
+}
+
+// String returns the shortest decimal representation of b that rounds back to b.
+func (b BigFloat) String() string {
+    return b.f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter.
+func (b BigFloat) Format(s fmt.State, verb rune) {
+    formatFloat(s, verb, b.f, b.String())
+}
+
+// result creates a big.Float with the precision of b to hold the result of an operation.
+func (b BigFloat) result() *big.Float {
+    return new(big.Float).SetPrec(b.f.Prec())
+}
+
+// operand converts the operand of an operation into a big.Float with the precision of b.
+// Infinite operands are errors, since big.Float panics on operations like 0 * Inf.
+func (b BigFloat) operand(n Number) (*big.Float, error) {
+    other, err := toBigFloat(n, b.f.Prec())
+    if err != nil {
+        return nil, err
+    }
+    if b.f.IsInf() || other.IsInf() {
+        return nil, ErrInfinite
+    }
+    return other, nil
+}
+
+// finite returns the result of an operation, which is an error if it overflowed the exponent range of big.Float.
+func finite(f *big.Float) (Number, error) {
+    if f.IsInf() {
+        return nil, ErrInfinite
+    }
+    return BigFloat{f: f}, nil
+}
+
+// toBigFloat converts a Number of any backend into a big.Float of the given precision.
+func toBigFloat(n Number, precision uint) (*big.Float, error) {
+    switch n := n.(type) {
+    case BigFloat:
+        return new(big.Float).SetPrec(precision).Set(n.f), nil
+    case Rat:
+        return new(big.Float).SetPrec(precision).SetRat(n.r), nil
+    case Int:
+        return new(big.Float).SetPrec(precision).SetInt64(int64(n)), nil
+    default:
+        f, err := toFloat(n)
+        if err != nil {
+            return nil, err
+        }
+        if math.IsNaN(f) {
+            return nil, ErrNotANumber
+        }
+        return new(big.Float).SetPrec(precision).SetFloat64(f), nil
+    }
+}
+
+// truncBigFloat sets f to its integer part, rounded toward zero, and returns f.
+func truncBigFloat(f *big.Float) *big.Float {
+    if f.IsInf() || f.IsInt() {
+        return f
+    }
+    i, _ := f.Int(nil)
+    return f.SetInt(i)
+}
+
+// floorBigFloat sets f to the greatest integer less than or equal to f, and returns f.
+func floorBigFloat(f *big.Float) *big.Float {
+    negative := f.Sign() < 0 && !f.IsInt()
+    truncBigFloat(f)
+    if negative {
+        f.Sub(f, big.NewFloat(1))
+    }
+    return f
+}
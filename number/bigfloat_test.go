@@ -0,0 +1,72 @@
+package number
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestBigFloat(t *testing.T) {
+    backend := NewBigFloatBackend(DefaultPrecision)
+    testCases := []struct {
+        name   string
+        a      string
+        b      string
+        op     func(a, b Number) (Number, error)
+        result string
+        err    error
+    }{
+        {name: "add", a: "0.1", b: "0.2", op: Number.Add, result: "0.3"},
+        {name: "sub", a: "2.5", b: "0.5", op: Number.Sub, result: "2"},
+        {name: "mul", a: "1.5", b: "4", op: Number.Mul, result: "6"},
+        {name: "quo", a: "3", b: "2", op: Number.Quo, result: "1.5"},
+        {name: "quo", a: "1", b: "0", op: Number.Quo, err: ErrZeroDivision},
+        {name: "floor quo", a: "-7", b: "2", op: Number.FloorQuo, result: "-4"},
+        {name: "floor quo", a: "1e30", b: "7", op: Number.FloorQuo, result: "1.42857142857142857142857142857e+29"},
+        {name: "floor quo", a: "1", b: "0", op: Number.FloorQuo, err: ErrZeroDivision},
+        {name: "mod", a: "-17", b: "5", op: Number.Mod, result: "3"},
+        {name: "mod", a: "7.5", b: "-2", op: Number.Mod, result: "-0.5"},
+        {name: "rem", a: "-17", b: "5", op: Number.Rem, result: "-2"},
+        {name: "rem", a: "7.5", b: "-2", op: Number.Rem, result: "1.5"},
+        {name: "rem", a: "1", b: "0", op: Number.Rem, err: ErrZeroDivision},
+        {name: "floor quo", a: "Inf", b: "3", op: Number.FloorQuo, err: ErrInfinite},
+        {name: "mod", a: "1", b: "Inf", op: Number.Mod, err: ErrInfinite},
+        {name: "mod", a: "Inf", b: "3", op: Number.Mod, err: ErrInfinite},
+        {name: "rem", a: "-Inf", b: "3", op: Number.Rem, err: ErrInfinite},
+        {name: "pow", a: "2", b: "200", op: Number.Pow, result: "1.606938044258990275541962092341162602522202993782792835301376e+60"},
+        {name: "pow", a: "2", b: "-3", op: Number.Pow, result: "0.125"},
+        {name: "pow", a: "4", b: "0.5", op: Number.Pow, result: "2"},
+        {name: "pow", a: "0", b: "-1", op: Number.Pow, err: ErrZeroDivision},
+        {name: "pow", a: "-1", b: "0.5", op: Number.Pow, err: ErrNotANumber},
+        {name: "pow", a: "2", b: "9223372036854775807", op: Number.Pow, err: ErrExponentTooLarge},
+        {name: "pow", a: "0.5", b: "-9223372036854775807", op: Number.Pow, err: ErrExponentTooLarge},
+        {name: "pow", a: "2", b: "-9223372036854775807", op: Number.Pow, result: "0"},
+        {name: "pow", a: "2", b: "-9223372036854775808", op: Number.Pow, err: ErrExponentTooLarge},
+        {name: "add", a: "Inf", b: "1", op: Number.Add, err: ErrInfinite},
+        {name: "sub", a: "1", b: "-Inf", op: Number.Sub, err: ErrInfinite},
+        {name: "mul", a: "0", b: "Inf", op: Number.Mul, err: ErrInfinite},
+        {name: "quo", a: "Inf", b: "Inf", op: Number.Quo, err: ErrInfinite},
+        {name: "mul", a: "1e600000000", b: "1e600000000", op: Number.Mul, err: ErrInfinite},
+    }
+
+    for _, tc := range testCases {
+        a, _ := backend.Parse(tc.a)
+        b, _ := backend.Parse(tc.b)
+        n, err := tc.op(a, b)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error %s %s %s: expected error %v, got %v.\n", tc.name, tc.a, tc.b, tc.err, err)
+            continue
+        }
+        if err == nil && n.String() != tc.result {
+            t.Errorf("Error %s %s %s: expected %s, got %s.\n", tc.name, tc.a, tc.b, tc.result, n)
+        }
+    }
+
+    // The precision of the backend is kept in the results.
+    low := NewBigFloatBackend(16)
+    one, _ := low.Parse("1")
+    three, _ := low.Parse("3")
+    n, _ := one.Quo(three)
+    if n.(BigFloat).BigFloat().Prec() != 16 {
+        t.Errorf("Error result precision: expected 16, got %d.\n", n.(BigFloat).BigFloat().Prec())
+    }
+}
@@ -0,0 +1,200 @@
+package number
+
+import (
+    "fmt"
+    "math"
+    "math/cmplx"
+    "strconv"
+)
+
+// ComplexBackend evaluates equations with complex128.
+var ComplexBackend Backend = complexBackend{}
+
+type complexBackend struct{}
+
+// Parse converts a literal into a real Complex.
+func (complexBackend) Parse(literal string) (Number, error) {
+    f, err := strconv.ParseFloat(literal, 64)
+    if err != nil {
+        return nil, ErrInvalidLiteral
+    }
+    return Complex(complex(f, 0)), nil
+}
+
+// Convert converts a Number into a Complex.
+func (complexBackend) Convert(n Number) (Number, error) {
+    return Complex(toComplex(n)), nil
+}
+
+// String returns the name of the backend.
+func (complexBackend) String() string {
+    return COMPLEX
+}
+
+// Complex is a complex128 Number.
+type Complex complex128
+
+// Add returns c + n.
+func (c Complex) Add(n Number) (Number, error) {
+    return c + Complex(toComplex(n)), nil
+}
+
+// Sub returns c - n.
+func (c Complex) Sub(n Number) (Number, error) {
+    return c - Complex(toComplex(n)), nil
+}
+
+// Mul returns c * n.
+func (c Complex) Mul(n Number) (Number, error) {
+    return c * Complex(toComplex(n)), nil
+}
+
+// Quo returns c / n.
+func (c Complex) Quo(n Number) (Number, error) {
+    other := toComplex(n)
+    if other == 0 {
+        return nil, ErrZeroDivision
+    }
+    return c / Complex(other), nil
+}
+
+// Pow returns c ^ n, the principal value of the power.
+// Integer exponents are calculated by multiplications and 0.5 by a square root, which are exact for results like i ^ 2 = -1,
+// where the polar form used in general leaves rounding errors in the parts that should be 0.
+func (c Complex) Pow(n Number) (Number, error) {
+    exponent := toComplex(n)
+    k := real(exponent)
+    switch {
+    case imag(exponent) != 0:
+        return Complex(cmplx.Pow(complex128(c), exponent)), nil
+    case k == 0.5:
+        return Complex(cmplx.Sqrt(complex128(c))), nil
+    case k == math.Trunc(k) && math.Abs(k) <= maxExactExponent:
+        if k < 0 && c == 0 {
+            return nil, ErrZeroDivision
+        }
+        // Exponentiation by squaring.
+        result, square := complex128(1), complex128(c)
+        for e := int64(math.Abs(k)); e > 0; e >>= 1 {
+            if e&1 == 1 {
+                result *= square
+            }
+            square *= square
+        }
+        if k < 0 {
+            result = 1 / result
+        }
+        return Complex(result), nil
+    default:
+        return Complex(cmplx.Pow(complex128(c), exponent)), nil
+    }
+}
+
+// FloorQuo returns ⌊c / n⌋, both of them must be real.
+func (c Complex) FloorQuo(n Number) (Number, error) {
+    x, y, err := realOperands(c, n)
+    if err != nil {
+        return nil, err
+    }
+    return Complex(complex(FloorQuoFloat64(x, y), 0)), nil
+}
+
+// Mod returns c mod n, which has the sign of n. Both of them must be real.
+func (c Complex) Mod(n Number) (Number, error) {
+    x, y, err := realOperands(c, n)
+    if err != nil {
+        return nil, err
+    }
+    return Complex(complex(ModFloat64(x, y), 0)), nil
+}
+
+// Rem returns c rem n, which has the sign of c. Both of them must be real.
+func (c Complex) Rem(n Number) (Number, error) {
+    x, y, err := realOperands(c, n)
+    if err != nil {
+        return nil, err
+    }
+    return Complex(complex(math.Mod(x, y), 0)), nil
+}
+
+// Neg returns -c.
+// It's calculated as 0 - c, so the negation of 0 is 0 instead of -0.
+func (c Complex) Neg() (Number, error) {
+    return 0 - c, nil
+}
+
+// Cmp compares c and n.
+// Complex numbers aren't ordered, so both of them must be real.
+func (c Complex) Cmp(n Number) (int, error) {
+    other := toComplex(n)
+    if imag(c) != 0 || imag(other) != 0 {
+        return 0, ErrNotReal
+    }
+    return Float(real(c)).Cmp(Float(real(other)))
+}
+
+// IsZero reports whether c is 0.
+func (c Complex) IsZero() bool {
+    return c == 0
+}
+
+// Float64 returns the real part of c.
+func (c Complex) Float64() float64 {
+    return real(c)
+}
+
+// String returns c in the form of a + bi, with the shortest decimal representations of both parts.
+// Real numbers are returned without the imaginary part.
+func (c Complex) String() string {
+    return c.text(func(f float64) string {
+        return strconv.FormatFloat(f, 'g', -1, 64)
+    })
+}
+
+// Format implements fmt.Formatter.
+// The floating-point verbs format both parts, like %.4f gives 3.0000 + 4.0000i.
+func (c Complex) Format(s fmt.State, verb rune) {
+    switch verb {
+    case 's', 'v':
+        fmt.Fprintf(s, fmt.FormatString(s, verb), c.String())
+    default:
+        format := fmt.FormatString(s, verb)
+        fmt.Fprint(s, c.text(func(f float64) string {
+            return fmt.Sprintf(format, f)
+        }))
+    }
+}
+
+// text returns c in the form of a + bi, with each part formatted by the given function.
+func (c Complex) text(format func(float64) string) string {
+    re, im := real(c), imag(c)
+    if im == 0 {
+        return format(re)
+    }
+    sign := "+"
+    if math.Signbit(im) {
+        sign = "-"
+    }
+    return fmt.Sprintf("%s %s %si", format(re), sign, format(math.Abs(im)))
+}
+
+// toComplex converts a Number of any backend into a complex128.
+func toComplex(n Number) complex128 {
+    if c, ok := n.(Complex); ok {
+        return complex128(c)
+    }
+    return complex(n.Float64(), 0)
+}
+
+// realOperands returns the real parts of the operands of a division without an order on complex numbers, like the floored division.
+// It fails if either of them isn't real, or if the divisor is 0.
+func realOperands(c Complex, n Number) (float64, float64, error) {
+    other := toComplex(n)
+    if other == 0 {
+        return 0, 0, ErrZeroDivision
+    }
+    if imag(c) != 0 || imag(other) != 0 {
+        return 0, 0, ErrNotReal
+    }
+    return real(c), real(other), nil
+}
@@ -0,0 +1,54 @@
+package number
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestComplex(t *testing.T) {
+    testCases := []struct {
+        name   string
+        a      Complex
+        b      Complex
+        op     func(a, b Number) (Number, error)
+        result string
+        err    error
+    }{
+        {name: "add", a: 3 + 4i, b: 1 - 2i, op: Number.Add, result: "4 + 2i"},
+        {name: "sub", a: 3 + 4i, b: 1 - 2i, op: Number.Sub, result: "2 + 6i"},
+        {name: "mul", a: 3 + 4i, b: 1 - 2i, op: Number.Mul, result: "11 - 2i"},
+        {name: "quo", a: 11 - 2i, b: 1 - 2i, op: Number.Quo, result: "3 + 4i"},
+        {name: "quo", a: 1, b: 0, op: Number.Quo, err: ErrZeroDivision},
+        {name: "floor quo", a: -7, b: 2, op: Number.FloorQuo, result: "-4"},
+        {name: "mod", a: -7, b: 2, op: Number.Mod, result: "1"},
+        {name: "rem", a: -7, b: 2, op: Number.Rem, result: "-1"},
+        {name: "mod", a: 1i, b: 2, op: Number.Mod, err: ErrNotReal},
+        {name: "rem", a: 1i, b: 0, op: Number.Rem, err: ErrZeroDivision},
+        {name: "pow", a: 1i, b: 2, op: Number.Pow, result: "-1"},
+        {name: "pow", a: 1 + 1i, b: -2, op: Number.Pow, result: "0 - 0.5i"},
+        {name: "pow", a: -4, b: 0.5, op: Number.Pow, result: "0 + 2i"},
+        {name: "pow", a: 0, b: -1, op: Number.Pow, err: ErrZeroDivision},
+    }
+
+    for _, tc := range testCases {
+        n, err := tc.op(tc.a, tc.b)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error %s %v %v: expected error %v, got %v.\n", tc.name, tc.a, tc.b, tc.err, err)
+            continue
+        }
+        if err == nil && n.String() != tc.result {
+            t.Errorf("Error %s %v %v: expected %s, got %s.\n", tc.name, tc.a, tc.b, tc.result, n)
+        }
+    }
+
+    // Complex numbers can't be used where real numbers are expected.
+    if _, err := Complex(1i).Cmp(Complex(1)); !errors.Is(err, ErrNotReal) {
+        t.Errorf("Error comparing complex numbers: expected error %v, got %v.\n", ErrNotReal, err)
+    }
+    if _, err := Float(1).Add(Complex(1i)); !errors.Is(err, ErrNotReal) {
+        t.Errorf("Error adding a complex number to a float: expected error %v, got %v.\n", ErrNotReal, err)
+    }
+    if _, err := RatBackend.Convert(Complex(1i)); !errors.Is(err, ErrNotReal) {
+        t.Errorf("Error converting a complex number to a rational: expected error %v, got %v.\n", ErrNotReal, err)
+    }
+}
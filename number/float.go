@@ -0,0 +1,204 @@
+package number
+
+import (
+    "fmt"
+    "math"
+    "strconv"
+)
+
+// FloatBackend evaluates equations with float64.
+var FloatBackend Backend = floatBackend{}
+
+type floatBackend struct{}
+
+// Parse converts a literal into a Float.
+func (floatBackend) Parse(literal string) (Number, error) {
+    f, err := strconv.ParseFloat(literal, 64)
+    if err != nil {
+        return nil, ErrInvalidLiteral
+    }
+    return Float(f), nil
+}
+
+// Convert converts a Number into a Float.
+func (floatBackend) Convert(n Number) (Number, error) {
+    f, err := toFloat(n)
+    if err != nil {
+        return nil, err
+    }
+    return Float(f), nil
+}
+
+// String returns the name of the backend.
+func (floatBackend) String() string {
+    return FLOAT
+}
+
+// Float is a float64 Number.
+type Float float64
+
+// Add returns f + n.
+func (f Float) Add(n Number) (Number, error) {
+    other, err := toFloat(n)
+    if err != nil {
+        return nil, err
+    }
+    return f + Float(other), nil
+}
+
+// Sub returns f - n.
+func (f Float) Sub(n Number) (Number, error) {
+    other, err := toFloat(n)
+    if err != nil {
+        return nil, err
+    }
+    return f - Float(other), nil
+}
+
+// Mul returns f * n.
+func (f Float) Mul(n Number) (Number, error) {
+    other, err := toFloat(n)
+    if err != nil {
+        return nil, err
+    }
+    return f * Float(other), nil
+}
+
+// Quo returns f / n.
+func (f Float) Quo(n Number) (Number, error) {
+    other, err := toFloat(n)
+    if err != nil {
+        return nil, err
+    }
+    if other == 0 {
+        return nil, ErrZeroDivision
+    }
+    return f / Float(other), nil
+}
+
+// Pow returns f ^ n.
+func (f Float) Pow(n Number) (Number, error) {
+    other, err := toFloat(n)
+    if err != nil {
+        return nil, err
+    }
+    return Float(math.Pow(float64(f), other)), nil
+}
+
+// FloorQuo returns ⌊f / n⌋.
+func (f Float) FloorQuo(n Number) (Number, error) {
+    other, err := toFloat(n)
+    if err != nil {
+        return nil, err
+    }
+    if other == 0 {
+        return nil, ErrZeroDivision
+    }
+    return Float(FloorQuoFloat64(float64(f), other)), nil
+}
+
+// Mod returns f mod n, which has the sign of n.
+func (f Float) Mod(n Number) (Number, error) {
+    other, err := toFloat(n)
+    if err != nil {
+        return nil, err
+    }
+    if other == 0 {
+        return nil, ErrZeroDivision
+    }
+    return Float(ModFloat64(float64(f), other)), nil
+}
+
+// Rem returns f rem n, which has the sign of f.
+func (f Float) Rem(n Number) (Number, error) {
+    other, err := toFloat(n)
+    if err != nil {
+        return nil, err
+    }
+    if other == 0 {
+        return nil, ErrZeroDivision
+    }
+    return Float(math.Mod(float64(f), other)), nil
+}
+
+// Neg returns -f.
+// It's calculated as 0 - f, so the negation of 0 is 0 instead of -0.
+func (f Float) Neg() (Number, error) {
+    return 0 - f, nil
+}
+
+// Cmp compares f and n.
+func (f Float) Cmp(n Number) (int, error) {
+    other, err := toFloat(n)
+    if err != nil {
+        return 0, err
+    }
+    switch {
+    case math.IsNaN(float64(f)) || math.IsNaN(other):
+        return 0, ErrNotANumber
+    case float64(f) < other:
+        return -1, nil
+    case float64(f) > other:
+        return 1, nil
+    default:
+        return 0, nil
+    }
+}
+
+// IsZero reports whether f is 0.
+func (f Float) IsZero() bool {
+    return f == 0
+}
+
+// Float64 returns f as a float64.
+func (f Float) Float64() float64 {
+    return float64(f)
+}
+
+// String returns the shortest decimal representation of f.
+func (f Float) String() string {
+    return strconv.FormatFloat(float64(f), 'g', -1, 64)
+}
+
+// Format implements fmt.Formatter.
+func (f Float) Format(s fmt.State, verb rune) {
+    formatFloat(s, verb, float64(f), f.String())
+}
+
+// toFloat converts a real Number of any backend into a float64.
+func toFloat(n Number) (float64, error) {
+    if c, ok := n.(Complex); ok && imag(c) != 0 {
+        return 0, ErrNotReal
+    }
+    return n.Float64(), nil
+}
+
+// FloorQuoFloat64 returns ⌊x / y⌋ of float64, derived from the remainder so that x = y * ⌊x / y⌋ + x mod y, like 1 // 0.1 = 9.
+// ⌊x / y⌋ of the rounded quotient could be one more, like 10 for 1 // 0.1, when the remainder is close to y.
+// It's exported for evaluators of float64 which can't afford a Number, like package vm.
+func FloorQuoFloat64(x, y float64) float64 {
+    m := math.Mod(x, y)
+    q := (x - m) / y
+    if m != 0 && (m < 0) != (y < 0) {
+        q--
+    }
+    // x - m is a multiple of y, so the quotient only needs rounding away its error.
+    floor := math.Floor(q)
+    switch {
+    case q-floor > 0.5:
+        floor++
+    case floor == 0:
+        // Like Neg, the quotient is 0 instead of -0.
+        return 0
+    }
+    return floor
+}
+
+// ModFloat64 returns x mod y of float64, the remainder of math.Mod moved to the sign of y.
+func ModFloat64(x, y float64) float64 {
+    m := math.Mod(x, y)
+    if m != 0 && (m < 0) != (y < 0) {
+        m += y
+    }
+    return m
+}
@@ -0,0 +1,72 @@
+package number
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestFloat(t *testing.T) {
+    a, b := Float(6), Float(3)
+    operations := []struct {
+        name   string
+        op     func(Number) (Number, error)
+        result string
+    }{
+        {name: "add", op: a.Add, result: "9"},
+        {name: "sub", op: a.Sub, result: "3"},
+        {name: "mul", op: a.Mul, result: "18"},
+        {name: "quo", op: a.Quo, result: "2"},
+        {name: "pow", op: a.Pow, result: "216"},
+        {name: "floor quo", op: Float(-7).FloorQuo, result: "-3"},
+        {name: "mod", op: Float(-7).Mod, result: "2"},
+        {name: "rem", op: Float(-7).Rem, result: "-1"},
+        {name: "mod", op: Float(7.5).Mod, result: "1.5"},
+    }
+
+    for _, tc := range operations {
+        n, err := tc.op(b)
+        if err != nil {
+            t.Errorf("Error %s: got error %v.\n", tc.name, err)
+            continue
+        }
+        if n.String() != tc.result {
+            t.Errorf("Error %s: expected %s, got %s.\n", tc.name, tc.result, n)
+        }
+    }
+
+    for _, op := range []func(Number) (Number, error){a.Quo, a.FloorQuo, a.Mod, a.Rem} {
+        if _, err := op(Float(0)); !errors.Is(err, ErrZeroDivision) {
+            t.Errorf("Error dividing by 0: expected error %v, got %v.\n", ErrZeroDivision, err)
+        }
+    }
+
+    // The floor quotient is consistent with the modulo, x = y * (x // y) + x mod y, like 1 // 0.1 = 9 with 1 mod 0.1 = 0.09999999999999995.
+    quotients := []struct {
+        x, y     Float
+        quotient string
+        modulo   string
+    }{
+        {x: 1, y: 0.1, quotient: "9", modulo: "0.09999999999999995"},
+        {x: -1, y: 0.1, quotient: "-10", modulo: "5.551115123125783e-17"},
+        {x: 1, y: -0.1, quotient: "-10", modulo: "-5.551115123125783e-17"},
+        {x: -0.5, y: -3, quotient: "0", modulo: "-0.5"},
+        {x: 0.5, y: -3, quotient: "-1", modulo: "-2.5"},
+    }
+    for _, tc := range quotients {
+        q, _ := tc.x.FloorQuo(tc.y)
+        m, _ := tc.x.Mod(tc.y)
+        if q.String() != tc.quotient || m.String() != tc.modulo {
+            t.Errorf("Error floor quo and mod %s %s: expected %s and %s, got %s and %s.\n", tc.x, tc.y, tc.quotient, tc.modulo, q, m)
+        }
+    }
+
+    if n, _ := Float(0).Neg(); n.String() != "0" {
+        t.Errorf("Error negating 0: expected 0, got %s.\n", n)
+    }
+
+    // Literals are parsed in float64 precision.
+    n, err := FloatBackend.Parse("2.2")
+    if err != nil || n.Float64() != 2.2 {
+        t.Errorf("Error parsing 2.2: got %v, error %v.\n", n, err)
+    }
+}
@@ -0,0 +1,349 @@
+package number
+
+import (
+    "fmt"
+    "math"
+    "math/big"
+    "strconv"
+)
+
+// IntBackend evaluates equations with int64, failing on overflows instead of wrapping around.
+var IntBackend Backend = intBackend{}
+
+type intBackend struct{}
+
+// Parse converts a literal into an Int.
+// FLOAT literals are accepted as long as they're integral, like 2.0.
+func (intBackend) Parse(literal string) (Number, error) {
+    i, err := strconv.ParseInt(literal, 10, 64)
+    if err == nil {
+        return Int(i), nil
+    }
+    r, ok := new(big.Rat).SetString(literal)
+    if !ok {
+        return nil, ErrInvalidLiteral
+    }
+    return ratToInt(r)
+}
+
+// Convert converts a Number into an Int.
+func (intBackend) Convert(n Number) (Number, error) {
+    i, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    return Int(i), nil
+}
+
+// String returns the name of the backend.
+func (intBackend) String() string {
+    return INT
+}
+
+// Int is an int64 Number.
+type Int int64
+
+// Add returns i + n.
+func (i Int) Add(n Number) (Number, error) {
+    other, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    sum := int64(i) + other
+    // The sum overflows if it moves in the opposite direction of the operand.
+    if (other > 0 && sum < int64(i)) || (other < 0 && sum > int64(i)) {
+        return nil, ErrOverflow
+    }
+    return Int(sum), nil
+}
+
+// Sub returns i - n.
+func (i Int) Sub(n Number) (Number, error) {
+    other, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    diff := int64(i) - other
+    if (other > 0 && diff > int64(i)) || (other < 0 && diff < int64(i)) {
+        return nil, ErrOverflow
+    }
+    return Int(diff), nil
+}
+
+// Mul returns i * n.
+func (i Int) Mul(n Number) (Number, error) {
+    other, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    product, ok := mulInt64(int64(i), other)
+    if !ok {
+        return nil, ErrOverflow
+    }
+    return Int(product), nil
+}
+
+// Quo returns i / n, failing with ErrNotAnInteger when the division isn't exact instead of truncating, like 7 / 2.
+// FloorQuo divides with a remainder, like 7 // 2 = 3.
+func (i Int) Quo(n Number) (Number, error) {
+    other, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    if other == 0 {
+        return nil, ErrZeroDivision
+    }
+    if int64(i) == math.MinInt64 && other == -1 {
+        return nil, ErrOverflow
+    }
+    if int64(i)%other != 0 {
+        return nil, ErrNotAnInteger
+    }
+    return Int(int64(i) / other), nil
+}
+
+// Pow returns i ^ n.
+// Negative exponents only have integer results when i is 1 or -1.
+func (i Int) Pow(n Number) (Number, error) {
+    exponent, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+
+    if exponent < 0 {
+        switch i {
+        case 0:
+            return nil, ErrZeroDivision
+        case 1:
+            return Int(1), nil
+        case -1:
+            if exponent%2 == 0 {
+                return Int(1), nil
+            }
+            return Int(-1), nil
+        default:
+            return nil, ErrNotAnInteger
+        }
+    }
+
+    // Exponentiation by squaring. The square is only checked when it's still needed.
+    result, square := int64(1), int64(i)
+    var ok bool
+    for ; exponent > 0; exponent >>= 1 {
+        if exponent&1 == 1 {
+            if result, ok = mulInt64(result, square); !ok {
+                return nil, ErrOverflow
+            }
+        }
+        if exponent > 1 {
+            if square, ok = mulInt64(square, square); !ok {
+                return nil, ErrOverflow
+            }
+        }
+    }
+    return Int(result), nil
+}
+
+// FloorQuo returns ⌊i / n⌋.
+func (i Int) FloorQuo(n Number) (Number, error) {
+    other, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    if other == 0 {
+        return nil, ErrZeroDivision
+    }
+    if int64(i) == math.MinInt64 && other == -1 {
+        return nil, ErrOverflow
+    }
+    q := int64(i) / other
+    // The truncated quotient is one too large if the division isn't exact and the operands have different signs.
+    if int64(i)%other != 0 && (int64(i) < 0) != (other < 0) {
+        q--
+    }
+    return Int(q), nil
+}
+
+// Mod returns i mod n, which has the sign of n.
+func (i Int) Mod(n Number) (Number, error) {
+    other, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    if other == 0 {
+        return nil, ErrZeroDivision
+    }
+    m := int64(i) % other
+    if m != 0 && (m < 0) != (other < 0) {
+        m += other
+    }
+    return Int(m), nil
+}
+
+// Rem returns i rem n, which has the sign of i.
+func (i Int) Rem(n Number) (Number, error) {
+    other, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    if other == 0 {
+        return nil, ErrZeroDivision
+    }
+    return Int(int64(i) % other), nil
+}
+
+// Neg returns -i.
+func (i Int) Neg() (Number, error) {
+    if int64(i) == math.MinInt64 {
+        return nil, ErrOverflow
+    }
+    return -i, nil
+}
+
+// And returns the bitwise i & n.
+func (i Int) And(n Number) (Number, error) {
+    other, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    return i & Int(other), nil
+}
+
+// Or returns the bitwise i | n.
+func (i Int) Or(n Number) (Number, error) {
+    other, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    return i | Int(other), nil
+}
+
+// Xor returns the bitwise exclusive or of i and n.
+func (i Int) Xor(n Number) (Number, error) {
+    other, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    return i ^ Int(other), nil
+}
+
+// Shl returns i << n.
+// Like Mul, it fails instead of shifting bits out, so i << n is always i * 2 ^ n.
+func (i Int) Shl(n Number) (Number, error) {
+    count, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    if count < 0 {
+        return nil, ErrShiftCount
+    }
+    if i == 0 {
+        return Int(0), nil
+    }
+    if count >= 64 || (int64(i)<<count)>>count != int64(i) {
+        return nil, ErrOverflow
+    }
+    return Int(int64(i) << count), nil
+}
+
+// Shr returns i >> n, an arithmetic shift which keeps the sign of i.
+func (i Int) Shr(n Number) (Number, error) {
+    count, err := toInt(n)
+    if err != nil {
+        return nil, err
+    }
+    if count < 0 {
+        return nil, ErrShiftCount
+    }
+    return Int(int64(i) >> count), nil
+}
+
+// Not returns the bitwise complement ~i, which is -i - 1.
+func (i Int) Not() Int {
+    return ^i
+}
+
+// Cmp compares i and n.
+// Non-integer operands are compared exactly as rationals.
+func (i Int) Cmp(n Number) (int, error) {
+    if other, ok := n.(Int); ok {
+        switch {
+        case i < other:
+            return -1, nil
+        case i > other:
+            return 1, nil
+        default:
+            return 0, nil
+        }
+    }
+    other, err := toRat(n)
+    if err != nil {
+        return 0, err
+    }
+    return new(big.Rat).SetInt64(int64(i)).Cmp(other), nil
+}
+
+// IsZero reports whether i is 0.
+func (i Int) IsZero() bool {
+    return i == 0
+}
+
+// Float64 returns the nearest float64 of i.
+func (i Int) Float64() float64 {
+    return float64(i)
+}
+
+// String returns the decimal representation of i.
+func (i Int) String() string {
+    return strconv.FormatInt(int64(i), 10)
+}
+
+// Format implements fmt.Formatter.
+// The integer verbs like %d and %x format i as an int64, the floating-point verbs format i without losing precision.
+func (i Int) Format(s fmt.State, verb rune) {
+    switch verb {
+    case 'd', 'b', 'o', 'O', 'x', 'X', 'c', 'q', 'U':
+        fmt.Fprintf(s, fmt.FormatString(s, verb), int64(i))
+    default:
+        formatFloat(s, verb, new(big.Float).SetInt64(int64(i)), i.String())
+    }
+}
+
+// mulInt64 returns a * b, and whether the product fits in an int64.
+func mulInt64(a, b int64) (int64, bool) {
+    if a == 0 || b == 0 {
+        return 0, true
+    }
+    product := a * b
+    if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+        return 0, false
+    }
+    return product, true
+}
+
+// toInt converts an integral Number of any backend into an int64.
+func toInt(n Number) (int64, error) {
+    if i, ok := n.(Int); ok {
+        return int64(i), nil
+    }
+    r, err := toRat(n)
+    if err != nil {
+        return 0, err
+    }
+    i, err := ratToInt(r)
+    if err != nil {
+        return 0, err
+    }
+    return int64(i.(Int)), nil
+}
+
+// ratToInt converts an integral big.Rat into an Int.
+func ratToInt(r *big.Rat) (Number, error) {
+    if !r.IsInt() {
+        return nil, ErrNotAnInteger
+    }
+    if !r.Num().IsInt64() {
+        return nil, ErrOverflow
+    }
+    return Int(r.Num().Int64()), nil
+}
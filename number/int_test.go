@@ -0,0 +1,114 @@
+package number
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestInt(t *testing.T) {
+    // The bitwise operations are methods of Int only.
+    bitwise := func(op func(Int, Number) (Number, error)) func(a, b Number) (Number, error) {
+        return func(a, b Number) (Number, error) {
+            return op(a.(Int), b)
+        }
+    }
+
+    testCases := []struct {
+        name   string
+        a      string
+        b      string
+        op     func(a, b Number) (Number, error)
+        result string
+        err    error
+    }{
+        {name: "add", a: "6", b: "3", op: Number.Add, result: "9"},
+        {name: "add", a: "9223372036854775807", b: "1", op: Number.Add, err: ErrOverflow},
+        {name: "add", a: "-9223372036854775808", b: "-1", op: Number.Add, err: ErrOverflow},
+        {name: "sub", a: "6", b: "9", op: Number.Sub, result: "-3"},
+        {name: "sub", a: "-9223372036854775808", b: "1", op: Number.Sub, err: ErrOverflow},
+        {name: "mul", a: "-6", b: "3", op: Number.Mul, result: "-18"},
+        {name: "mul", a: "4294967296", b: "4294967296", op: Number.Mul, err: ErrOverflow},
+        {name: "mul", a: "-9223372036854775808", b: "-1", op: Number.Mul, err: ErrOverflow},
+        {name: "quo", a: "8", b: "2", op: Number.Quo, result: "4"},
+        {name: "quo", a: "-8", b: "2", op: Number.Quo, result: "-4"},
+        {name: "quo", a: "7", b: "2", op: Number.Quo, err: ErrNotAnInteger},
+        {name: "quo", a: "-7", b: "2", op: Number.Quo, err: ErrNotAnInteger},
+        {name: "quo", a: "7", b: "0", op: Number.Quo, err: ErrZeroDivision},
+        {name: "quo", a: "-9223372036854775808", b: "-1", op: Number.Quo, err: ErrOverflow},
+        {name: "floor quo", a: "7", b: "2", op: Number.FloorQuo, result: "3"},
+        {name: "floor quo", a: "-7", b: "2", op: Number.FloorQuo, result: "-4"},
+        {name: "floor quo", a: "-8", b: "2", op: Number.FloorQuo, result: "-4"},
+        {name: "floor quo", a: "7", b: "0", op: Number.FloorQuo, err: ErrZeroDivision},
+        {name: "floor quo", a: "-9223372036854775808", b: "-1", op: Number.FloorQuo, err: ErrOverflow},
+        {name: "mod", a: "17", b: "5", op: Number.Mod, result: "2"},
+        {name: "mod", a: "-17", b: "5", op: Number.Mod, result: "3"},
+        {name: "mod", a: "17", b: "-5", op: Number.Mod, result: "-3"},
+        {name: "mod", a: "17", b: "0", op: Number.Mod, err: ErrZeroDivision},
+        {name: "rem", a: "-17", b: "5", op: Number.Rem, result: "-2"},
+        {name: "rem", a: "17", b: "-5", op: Number.Rem, result: "2"},
+        {name: "rem", a: "-9223372036854775808", b: "-1", op: Number.Rem, result: "0"},
+        {name: "rem", a: "17", b: "0", op: Number.Rem, err: ErrZeroDivision},
+        {name: "and", a: "12", b: "10", op: bitwise(Int.And), result: "8"},
+        {name: "or", a: "12", b: "10", op: bitwise(Int.Or), result: "14"},
+        {name: "xor", a: "12", b: "10", op: bitwise(Int.Xor), result: "6"},
+        {name: "and", a: "-1", b: "255", op: bitwise(Int.And), result: "255"},
+        {name: "shl", a: "1", b: "62", op: bitwise(Int.Shl), result: "4611686018427387904"},
+        {name: "shl", a: "1", b: "63", op: bitwise(Int.Shl), err: ErrOverflow},
+        {name: "shl", a: "-1", b: "63", op: bitwise(Int.Shl), result: "-9223372036854775808"},
+        {name: "shl", a: "0", b: "100", op: bitwise(Int.Shl), result: "0"},
+        {name: "shl", a: "1", b: "-1", op: bitwise(Int.Shl), err: ErrShiftCount},
+        {name: "shr", a: "-16", b: "2", op: bitwise(Int.Shr), result: "-4"},
+        {name: "shr", a: "16", b: "100", op: bitwise(Int.Shr), result: "0"},
+        {name: "shr", a: "16", b: "-1", op: bitwise(Int.Shr), err: ErrShiftCount},
+        {name: "pow", a: "2", b: "62", op: Number.Pow, result: "4611686018427387904"},
+        {name: "pow", a: "2", b: "63", op: Number.Pow, err: ErrOverflow},
+        {name: "pow", a: "-2", b: "63", op: Number.Pow, result: "-9223372036854775808"},
+        {name: "pow", a: "-1", b: "-3", op: Number.Pow, result: "-1"},
+        {name: "pow", a: "2", b: "-1", op: Number.Pow, err: ErrNotAnInteger},
+        {name: "pow", a: "0", b: "-1", op: Number.Pow, err: ErrZeroDivision},
+    }
+
+    for _, tc := range testCases {
+        a, _ := IntBackend.Parse(tc.a)
+        b, _ := IntBackend.Parse(tc.b)
+        n, err := tc.op(a, b)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error %s %s %s: expected error %v, got %v.\n", tc.name, tc.a, tc.b, tc.err, err)
+            continue
+        }
+        if err == nil && n.String() != tc.result {
+            t.Errorf("Error %s %s %s: expected %s, got %s.\n", tc.name, tc.a, tc.b, tc.result, n)
+        }
+    }
+
+    if Int(5).Not() != -6 {
+        t.Errorf("Error complementing 5: expected -6, got %s.\n", Int(5).Not())
+    }
+
+    if _, err := Int(-9223372036854775808).Neg(); !errors.Is(err, ErrOverflow) {
+        t.Errorf("Error negating the minimum int64: expected error %v, got %v.\n", ErrOverflow, err)
+    }
+
+    parseCases := []struct {
+        literal string
+        result  string
+        err     error
+    }{
+        {literal: "42", result: "42"},
+        {literal: "2.0", result: "2"},
+        {literal: "2.5", err: ErrNotAnInteger},
+        {literal: "9223372036854775808", err: ErrOverflow},
+        // Literals aren't wrapped into the sign bit, like 0xFFFFFFFFFFFFFFFF.
+        {literal: "18446744073709551615", err: ErrOverflow},
+    }
+    for _, tc := range parseCases {
+        n, err := IntBackend.Parse(tc.literal)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error parsing %s: expected error %v, got %v.\n", tc.literal, tc.err, err)
+            continue
+        }
+        if err == nil && n.String() != tc.result {
+            t.Errorf("Error parsing %s: expected %s, got %s.\n", tc.literal, tc.result, n)
+        }
+    }
+}
@@ -0,0 +1,130 @@
+/*
+Package number implements the numeric backends equations are evaluated with.
+A backend creates Numbers from literals, and Numbers do the arithmetic of the operators.
+
+The package comes with backends for float64, big.Rat, big.Float, int64 and complex128.
+Embedders can supply their own arithmetic by implementing Number and Backend, then setting the backend on a parser.
+*/
+package number
+
+import (
+    "errors"
+    "fmt"
+)
+
+const (
+    FLOAT    = "float"
+    RATIONAL = "rational"
+    BIGFLOAT = "bigfloat"
+    INT      = "int"
+    COMPLEX  = "complex"
+
+    // DefaultPrecision is the precision of the bigfloat backend in bits, if none is given.
+    DefaultPrecision uint = 256
+
+    // maxExactExponent limits integer exponents of exact powers, larger exponents would exhaust the memory.
+    maxExactExponent = 1 << 20
+)
+
+var (
+    ErrZeroDivision     = errors.New("error cannot use 0 as denominator")
+    ErrInvalidLiteral   = errors.New("error invalid number literal")
+    ErrNotANumber       = errors.New("error result is not a number")
+    ErrExponentTooLarge = errors.New("error exponent too large")
+    ErrOverflow         = errors.New("error integer overflow")
+    ErrNotAnInteger     = errors.New("error number is not an integer")
+    ErrNotReal          = errors.New("error number is not a real number")
+    ErrUnknownBackend   = errors.New("error unknown numeric backend")
+    ErrShiftCount       = errors.New("error negative shift count")
+    ErrInfinite         = errors.New("error number is infinite")
+)
+
+// Number is a numeric value of a backend.
+// Numbers are immutable, operations always return a new Number.
+// The operand of an operation might come from another backend, it is converted to the backend of the receiver first.
+// Operations fail with an error when the result can't be represented, like a division by zero or an integer overflow.
+type Number interface {
+    Add(Number) (Number, error)
+    Sub(Number) (Number, error)
+    Mul(Number) (Number, error)
+    Quo(Number) (Number, error)
+    Pow(Number) (Number, error)
+    // FloorQuo returns the quotient rounded toward negative infinity, like 7 // -2 = -4.
+    FloorQuo(Number) (Number, error)
+    // Mod returns the modulo of the floored division, which has the sign of the divisor, like 7 % -2 = -1.
+    Mod(Number) (Number, error)
+    // Rem returns the remainder of the truncated division, which has the sign of the dividend, like 7 rem -2 = 1.
+    Rem(Number) (Number, error)
+    Neg() (Number, error)
+    // Cmp compares the Number to another, returning -1, 0 or +1 like big.Float.Cmp.
+    // It fails if the Numbers aren't ordered, like complex numbers.
+    Cmp(Number) (int, error)
+    IsZero() bool
+    // Float64 returns the nearest float64 of the Number, or of its real part.
+    Float64() float64
+    // String returns the Number in full precision.
+    String() string
+    // Format formats the Number with the floating-point verbs like %.4f, %e and %g, %s and %v print String.
+    fmt.Formatter
+}
+
+// Backend creates the Numbers an equation is evaluated with.
+type Backend interface {
+    // Parse converts an INT or FLOAT literal into a Number.
+    Parse(literal string) (Number, error)
+    // Convert converts a Number of any backend into a Number of this backend.
+    // It fails if the backend can't represent the Number, like NaN in exact arithmetic.
+    Convert(n Number) (Number, error)
+    // String returns the name of the backend.
+    String() string
+}
+
+// NewBackend returns the backend of the given name.
+// The precision in bits only applies to the bigfloat backend, 0 means DefaultPrecision.
+func NewBackend(name string, precision uint) (Backend, error) {
+    switch name {
+    case FLOAT:
+        return FloatBackend, nil
+    case RATIONAL:
+        return RatBackend, nil
+    case BIGFLOAT:
+        if precision == 0 {
+            precision = DefaultPrecision
+        }
+        return NewBigFloatBackend(precision), nil
+    case INT:
+        return IntBackend, nil
+    case COMPLEX:
+        return ComplexBackend, nil
+    default:
+        return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, name)
+    }
+}
+
+// BackendOf returns the backend a Number belongs to.
+// Numbers of types from outside the package belong to FloatBackend.
+func BackendOf(n Number) Backend {
+    switch n := n.(type) {
+    case Rat:
+        return RatBackend
+    case BigFloat:
+        return NewBigFloatBackend(n.f.Prec())
+    case Int:
+        return IntBackend
+    case Complex:
+        return ComplexBackend
+    default:
+        return FloatBackend
+    }
+}
+
+// formatFloat formats a floating-point value, which is either a float64 or a *big.Float, for a Format method.
+// The verbs %s and %v print the text instead.
+func formatFloat(s fmt.State, verb rune, value any, text string) {
+    switch verb {
+    case 's', 'v':
+        fmt.Fprintf(s, fmt.FormatString(s, verb), text)
+    default:
+        fmt.Fprintf(s, fmt.FormatString(s, verb), value)
+    }
+}
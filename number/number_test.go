@@ -0,0 +1,138 @@
+package number
+
+import (
+    "errors"
+    "fmt"
+    "math/big"
+    "testing"
+)
+
+func TestNewBackend(t *testing.T) {
+    testCases := []struct {
+        name      string
+        precision uint
+        backend   string
+        err       error
+    }{
+        {name: FLOAT, backend: FLOAT},
+        {name: RATIONAL, backend: RATIONAL},
+        {name: BIGFLOAT, backend: BIGFLOAT},
+        {name: BIGFLOAT, precision: 64, backend: BIGFLOAT},
+        {name: INT, backend: INT},
+        {name: COMPLEX, backend: COMPLEX},
+        {name: "decimal", err: ErrUnknownBackend},
+    }
+
+    for _, tc := range testCases {
+        backend, err := NewBackend(tc.name, tc.precision)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error creating backend %s: expected error %v, got %v.\n", tc.name, tc.err, err)
+            continue
+        }
+        if err == nil && backend.String() != tc.backend {
+            t.Errorf("Error backend name: expected %s, got %s.\n", tc.backend, backend)
+        }
+    }
+}
+
+func TestBackend_Convert(t *testing.T) {
+    backends := []Backend{FloatBackend, ComplexBackend, RatBackend, NewBigFloatBackend(DefaultPrecision)}
+
+    // Converting between all backends keeps the value.
+    for _, from := range backends {
+        n, err := from.Parse("2.5")
+        if err != nil {
+            t.Fatalf("Error parsing literal with %s: %v.\n", from, err)
+        }
+        for _, to := range backends {
+            converted, err := to.Convert(n)
+            if err != nil {
+                t.Errorf("Error converting from %s to %s: %v.\n", from, to, err)
+                continue
+            }
+            if converted.String() != "2.5" {
+                t.Errorf("Error converting from %s to %s: expected 2.5, got %s.\n", from, to, converted)
+            }
+        }
+    }
+
+    // Exact backends can't represent NaN.
+    nan, _ := Float(-1).Pow(Float(0.5))
+    for _, to := range backends[2:] {
+        if _, err := to.Convert(nan); !errors.Is(err, ErrNotANumber) {
+            t.Errorf("Error converting NaN to %s: expected error %v, got %v.\n", to, ErrNotANumber, err)
+        }
+    }
+}
+
+func TestNumber_Cmp(t *testing.T) {
+    testCases := []struct {
+        a      Number
+        b      Number
+        result int
+    }{
+        {a: Float(1), b: Float(2), result: -1},
+        {a: Float(2), b: Int(2), result: 0},
+        {a: Int(3), b: Float(2.5), result: 1},
+        {a: Int(9007199254740993), b: Int(9007199254740992), result: 1},
+        {a: NewRat(big.NewRat(1, 3)), b: Float(0.3333), result: 1},
+        {a: NewBigFloat(big.NewFloat(-1)), b: NewRat(big.NewRat(-1, 1)), result: 0},
+        {a: Complex(2), b: Float(3), result: -1},
+    }
+
+    for _, tc := range testCases {
+        result, err := tc.a.Cmp(tc.b)
+        if err != nil {
+            t.Errorf("Error comparing %s and %s: got error %v.\n", tc.a, tc.b, err)
+            continue
+        }
+        if result != tc.result {
+            t.Errorf("Error comparing %s and %s: expected %d, got %d.\n", tc.a, tc.b, tc.result, result)
+        }
+    }
+}
+
+func TestNumber_Format(t *testing.T) {
+    testCases := []struct {
+        n      Number
+        format string
+        result string
+    }{
+        {n: Float(2), format: "%.4f", result: "2.0000"},
+        {n: Float(2), format: "%s", result: "2"},
+        {n: Int(255), format: "%.4f", result: "255.0000"},
+        {n: Int(255), format: "%x", result: "ff"},
+        {n: Int(255), format: "%v", result: "255"},
+        {n: NewRat(big.NewRat(2, 3)), format: "%.4f", result: "0.6667"},
+        {n: NewRat(big.NewRat(2, 3)), format: "%s", result: "2/3"},
+        {n: NewBigFloat(big.NewFloat(1.5)), format: "%.2e", result: "1.50e+00"},
+        {n: Complex(3 - 4i), format: "%.4f", result: "3.0000 - 4.0000i"},
+        {n: Complex(3 - 4i), format: "%v", result: "3 - 4i"},
+    }
+
+    for _, tc := range testCases {
+        result := fmt.Sprintf(tc.format, tc.n)
+        if result != tc.result {
+            t.Errorf("Error formatting %s with %s: expected %s, got %s.\n", tc.n, tc.format, tc.result, result)
+        }
+    }
+}
+
+func TestBackendOf(t *testing.T) {
+    testCases := []struct {
+        n       Number
+        backend string
+    }{
+        {n: Float(1), backend: FLOAT},
+        {n: NewRat(big.NewRat(1, 3)), backend: RATIONAL},
+        {n: NewBigFloat(big.NewFloat(1)), backend: BIGFLOAT},
+        {n: Int(1), backend: INT},
+        {n: Complex(1i), backend: COMPLEX},
+    }
+
+    for _, tc := range testCases {
+        if backend := BackendOf(tc.n); backend.String() != tc.backend {
+            t.Errorf("Error backend of %s: expected %s, got %s.\n", tc.n, tc.backend, backend)
+        }
+    }
+}
@@ -0,0 +1,271 @@
+package number
+
+import (
+    "fmt"
+    "math"
+    "math/big"
+    "strconv"
+)
+
+// RatBackend evaluates equations with exact rational arithmetic.
+var RatBackend Backend = ratBackend{}
+
+type ratBackend struct{}
+
+// Parse converts a literal into a Rat, without losing any precision.
+func (ratBackend) Parse(literal string) (Number, error) {
+    r, ok := new(big.Rat).SetString(literal)
+    if !ok {
+        return nil, ErrInvalidLiteral
+    }
+    return Rat{r: r}, nil
+}
+
+// Convert converts a Number into a Rat.
+func (ratBackend) Convert(n Number) (Number, error) {
+    r, err := toRat(n)
+    if err != nil {
+        return nil, err
+    }
+    return Rat{r: r}, nil
+}
+
+// String returns the name of the backend.
+func (ratBackend) String() string {
+    return RATIONAL
+}
+
+// Rat is an exact rational Number backed by big.Rat.
+type Rat struct {
+    r *big.Rat
+}
+
+// NewRat creates a Rat holding a copy of r.
+func NewRat(r *big.Rat) Rat {
+    return Rat{r: new(big.Rat).Set(r)}
+}
+
+// Rat returns a copy of the underlying big.Rat.
+func (r Rat) Rat() *big.Rat {
+    return new(big.Rat).Set(r.r)
+}
+
+// Add returns r + n.
+func (r Rat) Add(n Number) (Number, error) {
+    other, err := toRat(n)
+    if err != nil {
+        return nil, err
+    }
+    return Rat{r: new(big.Rat).Add(r.r, other)}, nil
+}
+
+// Sub returns r - n.
+func (r Rat) Sub(n Number) (Number, error) {
+    other, err := toRat(n)
+    if err != nil {
+        return nil, err
+    }
+    return Rat{r: new(big.Rat).Sub(r.r, other)}, nil
+}
+
+// Mul returns r * n.
+func (r Rat) Mul(n Number) (Number, error) {
+    other, err := toRat(n)
+    if err != nil {
+        return nil, err
+    }
+    return Rat{r: new(big.Rat).Mul(r.r, other)}, nil
+}
+
+// Quo returns r / n.
+func (r Rat) Quo(n Number) (Number, error) {
+    other, err := toRat(n)
+    if err != nil {
+        return nil, err
+    }
+    if other.Sign() == 0 {
+        return nil, ErrZeroDivision
+    }
+    return Rat{r: new(big.Rat).Quo(r.r, other)}, nil
+}
+
+// Pow returns r ^ n.
+// Integer exponents are exact. Other exponents generally have irrational results, so they're calculated in float64.
+func (r Rat) Pow(n Number) (Number, error) {
+    exponent, err := toRat(n)
+    if err != nil {
+        return nil, err
+    }
+
+    if !exponent.IsInt() {
+        f := math.Pow(r.Float64(), n.Float64())
+        if math.IsNaN(f) || math.IsInf(f, 0) {
+            return nil, ErrNotANumber
+        }
+        return Rat{r: floatToRat(f)}, nil
+    }
+
+    if !exponent.Num().IsInt64() {
+        return nil, ErrExponentTooLarge
+    }
+    k := exponent.Num().Int64()
+    if k > maxExactExponent || k < -maxExactExponent {
+        return nil, ErrExponentTooLarge
+    }
+    if k < 0 && r.r.Sign() == 0 {
+        return nil, ErrZeroDivision
+    }
+
+    // (a/b)^k = a^k / b^k, and (a/b)^-k = b^k / a^k.
+    negative := k < 0
+    if negative {
+        k = -k
+    }
+    exp := big.NewInt(k)
+    num := new(big.Int).Exp(r.r.Num(), exp, nil)
+    den := new(big.Int).Exp(r.r.Denom(), exp, nil)
+    if negative {
+        num, den = den, num
+    }
+    return Rat{r: new(big.Rat).SetFrac(num, den)}, nil
+}
+
+// FloorQuo returns ⌊r / n⌋.
+func (r Rat) FloorQuo(n Number) (Number, error) {
+    other, err := toRat(n)
+    if err != nil {
+        return nil, err
+    }
+    if other.Sign() == 0 {
+        return nil, ErrZeroDivision
+    }
+    return Rat{r: new(big.Rat).SetInt(floorRat(new(big.Rat).Quo(r.r, other)))}, nil
+}
+
+// Mod returns r mod n = r - n * ⌊r / n⌋, which has the sign of n.
+func (r Rat) Mod(n Number) (Number, error) {
+    other, err := toRat(n)
+    if err != nil {
+        return nil, err
+    }
+    if other.Sign() == 0 {
+        return nil, ErrZeroDivision
+    }
+    q := new(big.Rat).SetInt(floorRat(new(big.Rat).Quo(r.r, other)))
+    return Rat{r: q.Sub(r.r, q.Mul(q, other))}, nil
+}
+
+// Rem returns r rem n = r - n * trunc(r / n), which has the sign of r.
+func (r Rat) Rem(n Number) (Number, error) {
+    other, err := toRat(n)
+    if err != nil {
+        return nil, err
+    }
+    if other.Sign() == 0 {
+        return nil, ErrZeroDivision
+    }
+    q := new(big.Rat).Quo(r.r, other)
+    q.SetInt(new(big.Int).Quo(q.Num(), q.Denom()))
+    return Rat{r: q.Sub(r.r, q.Mul(q, other))}, nil
+}
+
+// Neg returns -r.
+func (r Rat) Neg() (Number, error) {
+    return Rat{r: new(big.Rat).Neg(r.r)}, nil
+}
+
+// Cmp compares r and n.
+func (r Rat) Cmp(n Number) (int, error) {
+    other, err := toRat(n)
+    if err != nil {
+        return 0, err
+    }
+    return r.r.Cmp(other), nil
+}
+
+// IsZero reports whether r is 0.
+func (r Rat) IsZero() bool {
+    return r.r.Sign() == 0
+}
+
+// Float64 returns the nearest float64 of r.
+func (r Rat) Float64() float64 {
+    f, _ := r.r.Float64()
+    return f
+}
+
+// String returns r as an exact decimal if it has a finite decimal representation, like 0.3.
+// Otherwise, it returns r as a fraction, like 1/3.
+func (r Rat) String() string {
+    if r.r.IsInt() {
+        return r.r.Num().String()
+    }
+
+    // A fraction has a finite decimal representation if its denominator has no prime factors other than 2 and 5.
+    // The amount of decimal places is the larger power of the two.
+    den := new(big.Int).Set(r.r.Denom())
+    twos := den.TrailingZeroBits()
+    den.Rsh(den, twos)
+
+    var fives uint
+    five := big.NewInt(5)
+    quo, rem := new(big.Int), new(big.Int)
+    for {
+        quo.QuoRem(den, five, rem)
+        if rem.Sign() != 0 {
+            break
+        }
+        den.Set(quo)
+        fives++
+    }
+
+    if den.Cmp(big.NewInt(1)) != 0 {
+        return r.r.String()
+    }
+    return r.r.FloatString(int(max(twos, fives)))
+}
+
+// Format implements fmt.Formatter.
+// The floating-point verbs round r from a big.Float with enough precision for both its numerator and its denominator.
+func (r Rat) Format(s fmt.State, verb rune) {
+    precision := uint(r.r.Num().BitLen()+r.r.Denom().BitLen()) + 64
+    formatFloat(s, verb, new(big.Float).SetPrec(precision).SetRat(r.r), r.String())
+}
+
+// toRat converts a Number of any backend into a big.Rat.
+func toRat(n Number) (*big.Rat, error) {
+    switch n := n.(type) {
+    case Rat:
+        return n.r, nil
+    case Int:
+        return new(big.Rat).SetInt64(int64(n)), nil
+    case BigFloat:
+        if n.f.IsInf() {
+            return nil, ErrNotANumber
+        }
+        r, _ := n.f.Rat(nil)
+        return r, nil
+    default:
+        f, err := toFloat(n)
+        if err != nil {
+            return nil, err
+        }
+        if math.IsNaN(f) || math.IsInf(f, 0) {
+            return nil, ErrNotANumber
+        }
+        return floatToRat(f), nil
+    }
+}
+
+// floatToRat converts a finite float64 into the big.Rat of its shortest decimal representation.
+// The exact binary value of a float64 has lots of noisy digits, like 0.1000000000000000055511151231257827.
+func floatToRat(f float64) *big.Rat {
+    r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+    return r
+}
+
+// floorRat returns the greatest integer less than or equal to r.
+// The denominator of a big.Rat is positive, so the Euclidean division of big.Int.Div rounds down.
+func floorRat(r *big.Rat) *big.Int {
+    return new(big.Int).Div(r.Num(), r.Denom())
+}
@@ -0,0 +1,60 @@
+package number
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestRat(t *testing.T) {
+    testCases := []struct {
+        name   string
+        a      string
+        b      string
+        op     func(a, b Number) (Number, error)
+        result string
+        err    error
+    }{
+        {name: "add", a: "0.1", b: "0.2", op: Number.Add, result: "0.3"},
+        {name: "sub", a: "0.3", b: "0.1", op: Number.Sub, result: "0.2"},
+        {name: "mul", a: "0.1", b: "0.1", op: Number.Mul, result: "0.01"},
+        {name: "quo", a: "1", b: "3", op: Number.Quo, result: "1/3"},
+        {name: "quo", a: "1", b: "8", op: Number.Quo, result: "0.125"},
+        {name: "quo", a: "1", b: "0", op: Number.Quo, err: ErrZeroDivision},
+        {name: "floor quo", a: "-7", b: "2", op: Number.FloorQuo, result: "-4"},
+        {name: "floor quo", a: "1", b: "0", op: Number.FloorQuo, err: ErrZeroDivision},
+        {name: "mod", a: "-17", b: "5", op: Number.Mod, result: "3"},
+        {name: "mod", a: "7.5", b: "-2", op: Number.Mod, result: "-0.5"},
+        {name: "mod", a: "1", b: "0", op: Number.Mod, err: ErrZeroDivision},
+        {name: "rem", a: "-17", b: "5", op: Number.Rem, result: "-2"},
+        {name: "rem", a: "7.5", b: "-2", op: Number.Rem, result: "1.5"},
+        {name: "rem", a: "1", b: "0", op: Number.Rem, err: ErrZeroDivision},
+        {name: "pow", a: "2", b: "200", op: Number.Pow, result: "1606938044258990275541962092341162602522202993782792835301376"},
+        {name: "pow", a: "2", b: "-2", op: Number.Pow, result: "0.25"},
+        {name: "pow", a: "-2", b: "3", op: Number.Pow, result: "-8"},
+        {name: "pow", a: "2", b: "0.5", op: Number.Pow, result: "1.4142135623730951"},
+        {name: "pow", a: "0", b: "-1", op: Number.Pow, err: ErrZeroDivision},
+        {name: "pow", a: "-1", b: "0.5", op: Number.Pow, err: ErrNotANumber},
+        {name: "pow", a: "2", b: "10000000", op: Number.Pow, err: ErrExponentTooLarge},
+    }
+
+    for _, tc := range testCases {
+        a, _ := RatBackend.Parse(tc.a)
+        b, _ := RatBackend.Parse(tc.b)
+        n, err := tc.op(a, b)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error %s %s %s: expected error %v, got %v.\n", tc.name, tc.a, tc.b, tc.err, err)
+            continue
+        }
+        if err == nil && n.String() != tc.result {
+            t.Errorf("Error %s %s %s: expected %s, got %s.\n", tc.name, tc.a, tc.b, tc.result, n)
+        }
+    }
+
+    // Operations never modify their operands.
+    a, _ := RatBackend.Parse("1.5")
+    _, _ = a.Add(a)
+    _, _ = a.Neg()
+    if a.String() != "1.5" {
+        t.Errorf("Error operand modified: expected 1.5, got %s.\n", a)
+    }
+}
@@ -0,0 +1,216 @@
+/*
+Package optimizer simplifies ast trees without changing their results.
+
+Optimize rewrites a tree bottom-up with these rules:
+
+  - Constant folding: operators and built-in function calls with constant operands are replaced by their value,
+    like '2 * 3' by '6'. Sub-trees failing to evaluate, like '1 / 0', or evaluating to complex numbers, infinities or NaN are kept.
+  - Identities: 'x * 1', '1 * x', 'x / 1', 'x + 0', '0 + x', 'x - 0' and 'x ^ 1' are replaced by 'x'.
+  - Prefix operators: '--x' and '+x' are replaced by 'x'.
+  - Canonical order: the operands of '+' and '*' are ordered by their S-expression, constants last,
+    so 'b + a' and 'a + b' are optimized into the same tree.
+
+None of the rules reassociate operators, so the rounding of the floating-point backends is the same as in the original tree.
+*/
+package optimizer
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/function"
+    "LexicalCalculator/number"
+    "LexicalCalculator/token"
+    "math"
+    "strings"
+)
+
+// Optimize returns an optimized copy of a tree, the tree itself isn't modified.
+// Calls are only folded if the function is the built-in function of the Environment, other functions might not be pure.
+func Optimize(n ast.Node, env *ast.Environment) ast.Node {
+    o := &optimizer{env: env}
+    return o.optimize(n)
+}
+
+// optimizer holds the Environment the functions of the tree are resolved in.
+type optimizer struct {
+    env *ast.Environment
+}
+
+// optimize optimizes a node after its children.
+func (o *optimizer) optimize(n ast.Node) ast.Node {
+    switch n := n.(type) {
+    case *ast.Assignment:
+        return ast.NewAssignment(n.Token, n.Target, o.optimize(n.Value))
+    case *ast.Call:
+        args := make([]ast.Node, len(n.Args))
+        foldable := o.isBuiltin(n.Name)
+        for i, arg := range n.Args {
+            args[i] = o.optimize(arg)
+            foldable = foldable && isLiteral(args[i])
+        }
+        call := ast.NewCall(n.Token, n.Name, args)
+        if foldable {
+            return fold(call)
+        }
+        return call
+    case *ast.Unary:
+        return o.optimizeUnary(n)
+    case *ast.Binary:
+        return o.optimizeBinary(n)
+    default:
+        // Literals and identifiers are already optimal.
+        return n
+    }
+}
+
+// optimizeUnary optimizes a prefix operator.
+func (o *optimizer) optimizeUnary(n *ast.Unary) ast.Node {
+    operand := o.optimize(n.Operand)
+    if n.Operator == "+" {
+        return operand
+    }
+    if inner, ok := operand.(*ast.Unary); ok && n.Operator == "-" && inner.Operator == "-" {
+        // The prefix '+' is already removed from the operand, so it's a double negation.
+        // Double complements aren't collapsed, '~~x' still fails if x isn't an integer.
+        return inner.Operand
+    }
+
+    node := ast.NewUnary(n.Token, n.Operator, operand)
+    if isLiteral(operand) {
+        return fold(node)
+    }
+    return node
+}
+
+// optimizeBinary optimizes an infix operator.
+func (o *optimizer) optimizeBinary(n *ast.Binary) ast.Node {
+    left, right := o.optimize(n.Left), o.optimize(n.Right)
+    if left == nil || right == nil {
+        return ast.NewBinary(n.Token, n.Operator, left, right)
+    }
+    if isLiteral(left) && isLiteral(right) {
+        return fold(ast.NewBinary(n.Token, n.Operator, left, right))
+    }
+
+    switch n.Operator {
+    case "+":
+        if isValue(right, 0) {
+            return left
+        }
+        if isValue(left, 0) {
+            return right
+        }
+    case "-":
+        if isValue(right, 0) {
+            return left
+        }
+    case "*":
+        if isValue(right, 1) {
+            return left
+        }
+        if isValue(left, 1) {
+            return right
+        }
+    case "/", "^":
+        if isValue(right, 1) {
+            return left
+        }
+    }
+
+    if (n.Operator == "+" || n.Operator == "*") && less(right, left) {
+        left, right = right, left
+    }
+    return ast.NewBinary(n.Token, n.Operator, left, right)
+}
+
+// isBuiltin checks whether a function name resolves to the built-in function in the Environment.
+func (o *optimizer) isBuiltin(name string) bool {
+    builtin, ok := function.Lookup(name)
+    if !ok {
+        return false
+    }
+    f, _ := o.env.Function(name)
+    return f == builtin
+}
+
+// fold replaces a node with constant operands by a literal of its result.
+// The node is kept if it fails to evaluate, so the error still happens when the tree is evaluated,
+// or if the result is a complex number, which has no literal.
+// Infinite and NaN results are kept too, their text like '+Inf' would parse back as a variable.
+func fold(n ast.Node) ast.Node {
+    value, err := ast.Evaluate(n)
+    if err != nil {
+        return n
+    }
+    if f := value.Float64(); math.IsInf(f, 0) || math.IsNaN(f) {
+        return n
+    }
+    if c, ok := value.(number.Complex); ok {
+        if imag(c) != 0 {
+            return n
+        }
+    }
+
+    literal := value.String()
+    lexicalType := token.INT
+    if strings.ContainsAny(literal, ".e/") {
+        lexicalType = token.FLOAT
+    }
+    tok := token.New(lexicalType, literal)
+    tok.Span = spanOf(n)
+    return ast.NewLiteral(tok, value)
+}
+
+// isLiteral checks whether a node is a literal.
+func isLiteral(n ast.Node) bool {
+    _, ok := n.(*ast.Literal)
+    return ok
+}
+
+// isValue checks whether a node is a literal equal to an integer.
+func isValue(n ast.Node, i int64) bool {
+    literal, ok := n.(*ast.Literal)
+    if !ok {
+        return false
+    }
+    cmp, err := literal.Value.Cmp(number.Int(i))
+    return err == nil && cmp == 0
+}
+
+// less reports whether a node is ordered before another in the canonical order of commutative operands.
+// Non-constant operands come first, ordered by their S-expression.
+func less(a ast.Node, b ast.Node) bool {
+    if isLiteral(a) != isLiteral(b) {
+        return !isLiteral(a)
+    }
+    return a.String() < b.String()
+}
+
+// spanOf returns the span covering all the tokens of a tree.
+func spanOf(n ast.Node) token.Span {
+    var span token.Span
+    found := false
+    var walk func(n ast.Node)
+    walk = func(n ast.Node) {
+        if n == nil {
+            return
+        }
+        if tok := n.Source(); tok != nil {
+            s := tok.Span
+            switch {
+            case !found:
+                span = s
+                found = true
+            case s.Offset < span.Offset:
+                s.Length = span.End() - s.Offset
+                span = s
+            case s.End() > span.End():
+                span.Length = s.End() - span.Offset
+            }
+        }
+        for _, child := range ast.Children(n) {
+            walk(child)
+        }
+    }
+    walk(n)
+    return span
+}
@@ -0,0 +1,120 @@
+package optimizer
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/function"
+    "LexicalCalculator/lexer"
+    "LexicalCalculator/number"
+    "LexicalCalculator/parser"
+    "testing"
+)
+
+func TestOptimize(t *testing.T) {
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        // Constant folding.
+        {input: "2 * 3 + x", result: "(+ x 6.0000)"},
+        {input: "x * (2 ^ 3 - sqrt(16))", result: "(* x 4.0000)"},
+        {input: "-(2 + 3) * x", result: "(* x -5.0000)"},
+        {input: "x / (1 / 0)", result: "(/ x (/ 1.0000 0.0000))"},
+        {input: "2i * 2 + x", result: "(+ (* 0.0000 + 2.0000i 2.0000) x)"},
+        {input: "max(x, 1 + 1)", result: "(max x 2.0000)"},
+        {input: "10 ^ 400 + x", result: "(+ (^ 10.0000 400.0000) x)"},
+        {input: "x * (10 ^ 400 - 10 ^ 400)", result: "(* (- (^ 10.0000 400.0000) (^ 10.0000 400.0000)) x)"},
+        // Identities.
+        {input: "x * 1", result: "x"},
+        {input: "1 * x", result: "x"},
+        {input: "x / 1", result: "x"},
+        {input: "x + 0", result: "x"},
+        {input: "0 + x", result: "x"},
+        {input: "x - 0", result: "x"},
+        {input: "x ^ 1", result: "x"},
+        {input: "x * (3 - 2) + (y - y * 1)", result: "(+ (- y y) x)"},
+        {input: "0 - x", result: "(- 0.0000 x)"},
+        {input: "1 / x", result: "(/ 1.0000 x)"},
+        // Prefix operators.
+        {input: "--x", result: "x"},
+        {input: "---x", result: "(- x)"},
+        {input: "+-+-x", result: "x"},
+        {input: "-(-(x))", result: "x"},
+        // Canonical order.
+        {input: "b + a", result: "(+ a b)"},
+        {input: "a + b", result: "(+ a b)"},
+        {input: "2 * x", result: "(* x 2.0000)"},
+        {input: "(y * x) + (b - a)", result: "(+ (* x y) (- b a))"},
+        {input: "b - a", result: "(- b a)"},
+        // Assignments.
+        {input: "y = x * 1 + 2 * 3", result: "(= y (+ x 6.0000))"},
+    }
+
+    for _, tc := range testCases {
+        n, err := parser.New(lexer.New()).ParseEquation(tc.input)
+        if err != nil {
+            t.Errorf("Error parsing %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        original := n.String()
+        optimized := Optimize(n, nil)
+        if optimized.String() != tc.result {
+            t.Errorf("Error optimizing %s: expected %s, got %s.\n", tc.input, tc.result, optimized)
+        }
+        if n.String() != original {
+            t.Errorf("Error optimizing %s: the original tree changed to %s.\n", tc.input, n)
+        }
+        // The optimized tree prints as an equation which parses and optimizes back into the same tree.
+        reparsed, err := parser.New(lexer.New()).ParseEquation(parser.Format(optimized))
+        if err != nil || Optimize(reparsed, nil).String() != optimized.String() {
+            t.Errorf("Error optimizing %s: %s doesn't parse back, got %v, error %v.\n", tc.input, parser.Format(optimized), reparsed, err)
+        }
+    }
+}
+
+func TestOptimize_Evaluate(t *testing.T) {
+    inputs := []string{
+        "(x + 1) * (y - 2) / (3 * 1) + 0",
+        "--x ^ 1 - y * (2 ^ -1)",
+        "hypot(y, x) * 1 + max(x, y, 2 + 2)",
+        "-(x * y) + -(-(2 * 3))",
+    }
+    values := []float64{-3.5, -1, 0, 0.1, 2, 7.25}
+
+    for _, input := range inputs {
+        n, err := parser.New(lexer.New()).ParseEquation(input)
+        if err != nil {
+            t.Errorf("Error parsing %s: got error %v.\n", input, err)
+            continue
+        }
+        optimized := Optimize(n, nil)
+
+        for _, x := range values {
+            for _, y := range values {
+                env := ast.NewEnvironment()
+                env.Set("x", number.Float(x))
+                env.Set("y", number.Float(y))
+                expected, expectedErr := ast.EvaluateIn(n, env)
+                re, err := ast.EvaluateIn(optimized, env)
+                if (err == nil) != (expectedErr == nil) || (err == nil && re.Float64() != expected.Float64()) {
+                    t.Errorf("Error evaluating the optimized %s with x = %f, y = %f: expected %v, got %v.\n", input, x, y, expected, re)
+                }
+            }
+        }
+    }
+}
+
+func TestOptimize_Functions(t *testing.T) {
+    // Functions of the Environment might not be pure, they aren't folded even if they shadow a built-in function.
+    env := ast.NewEnvironment()
+    env.SetFunction(&function.Function{Name: "sqrt", MinArgs: 1, MaxArgs: 1, Call: func(args []number.Number) (number.Number, error) {
+        return number.Float(-1), nil
+    }})
+
+    n := ast.NewCall(nil, "sqrt", []ast.Node{ast.NewLiteral(nil, number.Float(4))})
+    if optimized := Optimize(n, env); optimized.String() != "(sqrt 4.0000)" {
+        t.Errorf("Error optimizing sqrt of the Environment: expected (sqrt 4.0000), got %s.\n", optimized)
+    }
+    if optimized := Optimize(n, nil); optimized.String() != "2.0000" {
+        t.Errorf("Error optimizing the built-in sqrt: expected 2.0000, got %s.\n", optimized)
+    }
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+    "LexicalCalculator/token"
+    "fmt"
+)
+
+// Error is a parser diagnostic located at the offending token of the input.
+// It wraps one of the parser's error variables, so it can still be matched with errors.Is.
+type Error struct {
+    Err     error
+    Message string
+    Span    token.Span
+}
+
+// Error returns the human-readable message followed by the position of the offending token.
+// If there's no message, the message of the wrapped error is used instead.
+func (e *Error) Error() string {
+    message := e.Message
+    if message == "" {
+        message = e.Err.Error()
+    }
+    return fmt.Sprintf("%s at %s", message, e.Span)
+}
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error {
+    return e.Err
+}
+
+// ErrorList is the list of all errors found in a prompt, in the order they're found.
+type ErrorList []*Error
+
+// Error returns the message of the first error and how many errors follow.
+func (l ErrorList) Error() string {
+    switch len(l) {
+    case 0:
+        return "no errors"
+    case 1:
+        return l[0].Error()
+    }
+    return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// Unwrap returns all errors in the list, so errors.Is and errors.As can match any of them.
+func (l ErrorList) Unwrap() []error {
+    errs := make([]error, len(l))
+    for n, err := range l {
+        errs[n] = err
+    }
+    return errs
+}
+
+// Err returns the list as an error, or nil if the list is empty.
+func (l ErrorList) Err() error {
+    if len(l) == 0 {
+        return nil
+    }
+    return l
+}
@@ -0,0 +1,37 @@
+package parser
+
+import (
+    "LexicalCalculator/token"
+    "errors"
+    "testing"
+)
+
+func TestErrorList(t *testing.T) {
+    var empty ErrorList
+    if empty.Err() != nil {
+        t.Errorf("Error empty list: expected nil error, got %v.\n", empty.Err())
+    }
+
+    errList := ErrorList{
+        {Err: ErrEquation, Message: "missing operator between 5 and 25", Span: token.Span{Offset: 8, Line: 1, Column: 9, Length: 2}},
+        {Err: ErrClosingQuote, Span: token.Span{Offset: 11, Line: 1, Column: 12}},
+    }
+
+    err := errList.Err()
+    if !errors.Is(err, ErrEquation) || !errors.Is(err, ErrClosingQuote) {
+        t.Errorf("Error matching wrapped errors: expected both ErrEquation and ErrClosingQuote in %v.\n", err)
+    }
+    if errors.Is(err, ErrPrompt) {
+        t.Errorf("Error matching wrapped errors: unexpected ErrPrompt in %v.\n", err)
+    }
+
+    expected := "missing operator between 5 and 25 at line 1, column 9 (and 1 more errors)"
+    if err.Error() != expected {
+        t.Errorf("Error list message: expected %q, got %q.\n", expected, err.Error())
+    }
+
+    expected = "error missing prompt closing quote at line 1, column 12"
+    if errList[1].Error() != expected {
+        t.Errorf("Error message without Message: expected %q, got %q.\n", expected, errList[1].Error())
+    }
+}
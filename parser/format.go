@@ -0,0 +1,117 @@
+package parser
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/number"
+    "LexicalCalculator/token"
+    "fmt"
+    "math"
+    "strings"
+)
+
+// atomBindingPower is the binding power of nodes that never need parentheses, like identifiers and calls.
+const atomBindingPower = math.MaxInt
+
+// Format returns the infix form of a tree, which parses back into the same tree.
+// Only the parentheses the binding powers of the parser require are inserted, like '(1 + 2) * 3' but '1 + 2 * 3'.
+// Literals are printed in decimal, like '31i' for '0x1Fj', values without tokens are formatted by their numbers.
+func Format(n ast.Node) string {
+    switch n := n.(type) {
+    case *ast.Identifier:
+        return n.Name
+    case *ast.Literal:
+        return formatValue(n)
+    case *ast.Assignment:
+        return fmt.Sprintf("%s = %s", n.Target.Name, Format(n.Value))
+    case *ast.Call:
+        args := make([]string, len(n.Args))
+        for i, arg := range n.Args {
+            args[i] = Format(arg)
+        }
+        return fmt.Sprintf("%s(%s)", n.Name, strings.Join(args, ", "))
+    case *ast.Unary:
+        // The operand of a prefix operator is parsed with the right binding power of the prefix operator.
+        return n.Operator + formatOperand(n.Operand, prefixBindingPower(operatorToken(n.Operator)), false)
+    case *ast.Binary:
+        lbp, rbp := infixBindingPower(operatorToken(n.Operator))
+        return fmt.Sprintf("%s %s %s", formatOperand(n.Left, lbp, true), n.Operator, formatOperand(n.Right, rbp, false))
+    default:
+        return "0"
+    }
+}
+
+// formatOperand returns the infix form of an operand, wrapped in parentheses if the binding power of its operator requires.
+//
+// A left operand is parsed before its operator, so it's split by the operator if the operator binds at least as tightly
+// as the right side of the operand, like 'a + b' as the left operand of '*'.
+// A right operand is parsed with the right binding power of its operator, so it's split if its own operator binds less tightly,
+// like 'b - c' as the right operand of '-'.
+func formatOperand(n ast.Node, bp int, left bool) string {
+    lbp, rbp := bindingPowers(n)
+    s := Format(n)
+    if needsParentheses(lbp, rbp, bp, left) {
+        return "(" + s + ")"
+    }
+    return s
+}
+
+// needsParentheses checks whether an operand with the given binding powers is split by the binding power of its operator.
+func needsParentheses(lbp int, rbp int, bp int, left bool) bool {
+    return (left && bp >= rbp) || (!left && lbp < bp)
+}
+
+// bindingPowers returns the left and right binding powers a node is parsed with when it's formatted.
+// Prefix operators are only bound on the right, atoms aren't bound at all.
+func bindingPowers(n ast.Node) (int, int) {
+    switch n := n.(type) {
+    case *ast.Literal:
+        return valueBindingPowers(formatValue(n))
+    case *ast.Assignment:
+        // Assignments only exist at the start of an equation.
+        return 0, 0
+    case *ast.Unary:
+        return atomBindingPower, prefixBindingPower(operatorToken(n.Operator))
+    case *ast.Binary:
+        return infixBindingPower(operatorToken(n.Operator))
+    default:
+        return atomBindingPower, atomBindingPower
+    }
+}
+
+// valueBindingPowers returns the binding powers of the text of a value, which isn't a single literal if the value has no token,
+// like '-5', '1/3' or '1 + 2i'.
+func valueBindingPowers(s string) (int, int) {
+    switch {
+    case strings.Contains(s, " + ") || strings.Contains(s, " - "):
+        return infixBindingPower(token.New(token.PLUS, token.PLUS))
+    case strings.Contains(s, "/"):
+        return infixBindingPower(token.New(token.SLASH, token.SLASH))
+    case strings.HasPrefix(s, "-"):
+        return atomBindingPower, prefixBindingPower(token.New(token.MINUS, token.MINUS))
+    default:
+        return atomBindingPower, atomBindingPower
+    }
+}
+
+// operatorToken returns a token of an operator, the node of the operator might have been built without tokens.
+func operatorToken(operator string) *token.Token {
+    return token.New(operator, operator)
+}
+
+// formatValue returns the text of a literal.
+// Number literals are in decimal, without digit separators, like '31' for '0x1F' and '1000' for '1_000'.
+func formatValue(n *ast.Literal) string {
+    switch {
+    case n.Token != nil && (isInt(n.Token) || isFloat(n.Token)):
+        return decimalLiteral(n.Token.Literal)
+    case n.Token != nil && isImag(n.Token):
+        // The imaginary unit is either 'i' or 'j', it's always printed as 'i'.
+        return decimalLiteral(n.Token.Literal[:len(n.Token.Literal)-1]) + "i"
+    default:
+        if c, ok := n.Value.(number.Complex); ok && real(c) == 0 && imag(c) != 0 {
+            // Pure imaginary numbers have a literal.
+            return number.Float(imag(c)).String() + "i"
+        }
+        return n.Value.String()
+    }
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/lexer"
+    "LexicalCalculator/number"
+    "math/big"
+    "testing"
+)
+
+func TestFormat(t *testing.T) {
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        {input: "1+2*3", result: "1 + 2 * 3"},
+        {input: "(1+2)*3", result: "(1 + 2) * 3"},
+        {input: "((1 * 2)) + 3", result: "1 * 2 + 3"},
+        {input: "1 - (2 - 3)", result: "1 - (2 - 3)"},
+        {input: "(1 - 2) - 3", result: "1 - 2 - 3"},
+        {input: "1 / (2 * 3)", result: "1 / (2 * 3)"},
+        {input: "2 ^ (3 ^ 2)", result: "2 ^ 3 ^ 2"},
+        {input: "(2 ^ 3) ^ 2", result: "(2 ^ 3) ^ 2"},
+        {input: "2 ** 3 ** 2", result: "2 ^ 3 ^ 2"},
+        {input: "-(x+1)^2", result: "-(x + 1) ^ 2"},
+        {input: "-(x^2)", result: "-x ^ 2"},
+        {input: "(-x)^2", result: "(-x) ^ 2"},
+        {input: "-(x*2)", result: "-(x * 2)"},
+        {input: "(-x)*2", result: "-x * 2"},
+        {input: "2 * -x", result: "2 * -x"},
+        {input: "--x", result: "--x"},
+        {input: "17 mod 5 // (2 rem 3)", result: "17 % 5 // (2 rem 3)"},
+        {input: "(a * b) % c", result: "a * b % c"},
+        {input: "(x & 255) | (y << 8)", result: "x & 255 | y << 8"},
+        {input: "(x & 0xFF) | (y << 0b1_000)", result: "x & 255 | y << 8"},
+        {input: "0o17 + 1_000_000 + 2_5i", result: "15 + 1000000 + 25i"},
+        {input: "2j * x + 0x1fj", result: "2i * x + 31i"},
+        {input: "6.02E23*(1e-9)", result: "6.02E23 * 1e-9"},
+        {input: "x & (255 | y) << 8", result: "x & (255 | y) << 8"},
+        {input: "(a xor b) xor c", result: "a xor b xor c"},
+        {input: "~(x + 1) * -~x", result: "~(x + 1) * -~x"},
+        {input: "a * (b % c)", result: "a * (b % c)"},
+        {input: "[{2.50}] * 1.0", result: "2.50 * 1.0"},
+        {input: "max(1,(2.50),x)", result: "max(1, 2.50, x)"},
+        {input: "y=(2i*x)", result: "y = 2i * x"},
+        {input: "sqrt(x + 1) ^ 2", result: "sqrt(x + 1) ^ 2"},
+    }
+
+    for _, tc := range testCases {
+        n, err := New(lexer.New()).ParseEquation(tc.input)
+        if err != nil {
+            t.Errorf("Error parsing %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        s := Format(n)
+        if s != tc.result {
+            t.Errorf("Error formatting %s: expected %s, got %s.\n", tc.input, tc.result, s)
+        }
+
+        // The infix form parses back into the same tree.
+        formatted, err := New(lexer.New()).ParseEquation(s)
+        if err != nil || formatted.String() != n.String() {
+            t.Errorf("Error parsing the infix form %s of %s: expected %s, got %v, error %v.\n", s, tc.input, n, formatted, err)
+        }
+    }
+}
+
+func TestFormat_Value(t *testing.T) {
+    value := func(n number.Number) ast.Node { return ast.NewLiteral(nil, n) }
+    x := ast.NewIdentifier(nil, "x")
+
+    testCases := []struct {
+        node   ast.Node
+        result string
+    }{
+        // Values without tokens are formatted by their numbers, which might not be single literals.
+        {node: ast.NewBinary(nil, "^", value(number.Float(-5)), value(number.Complex(2i))), result: "(-5) ^ 2i"},
+        {node: ast.NewBinary(nil, "*", value(number.Float(-5)), x), result: "-5 * x"},
+        {node: ast.NewBinary(nil, "*", x, value(number.Complex(1-2i))), result: "x * (1 - 2i)"},
+        {node: ast.NewBinary(nil, "^", x, value(number.NewRat(big.NewRat(1, 3)))), result: "x ^ (1/3)"},
+        {node: ast.NewBinary(nil, "+", value(number.NewRat(big.NewRat(1, 3))), x), result: "1/3 + x"},
+    }
+
+    for _, tc := range testCases {
+        if s := Format(tc.node); s != tc.result {
+            t.Errorf("Error formatting %s: expected %s, got %s.\n", tc.node, tc.result, s)
+        }
+    }
+}
@@ -0,0 +1,224 @@
+package parser
+
+import (
+    "LexicalCalculator/ast"
+    "fmt"
+    "strings"
+)
+
+// greekLetters maps the identifiers named after greek letters to their symbols.
+var greekLetters = map[string]string{
+    "alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε", "zeta": "ζ", "eta": "η", "theta": "θ",
+    "iota": "ι", "kappa": "κ", "lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "pi": "π", "rho": "ρ",
+    "sigma": "σ", "tau": "τ", "upsilon": "υ", "phi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+}
+
+// mathFunctions maps the functions with a conventional name in mathematical notation to that name,
+// like 'asin' to 'arcsin'. LaTeX has an operator for each of them.
+var mathFunctions = map[string]string{
+    "sin": "sin", "cos": "cos", "tan": "tan", "asin": "arcsin", "acos": "arccos", "atan": "arctan",
+    "sinh": "sinh", "cosh": "cosh", "tanh": "tanh", "exp": "exp", "ln": "ln", "min": "min", "max": "max",
+}
+
+// latexOperators maps the operators without a symbol of their own in LaTeX to their LaTeX form.
+// Some of them, like '%' and '&', are special characters in LaTeX.
+var latexOperators = map[string]string{
+    "%": "\\bmod", "rem": "\\mathbin{\\mathrm{rem}}", "&": "\\mathbin{\\&}", "|": "\\mathbin{|}",
+    "xor": "\\oplus", "<<": "\\ll", ">>": "\\gg", "~": "\\lnot ",
+}
+
+// LaTeX returns the LaTeX form of a tree, like '\frac{1}{x} + \sqrt{x}^{2}' for '1 / x + sqrt(x) ^ 2'.
+// Divisions are fractions and powers are superscripts, so they need no parentheses, other operators are parenthesized like in Format.
+// Values keep the text of their literal tokens.
+func LaTeX(n ast.Node) string {
+    switch n := n.(type) {
+    case *ast.Identifier:
+        return latexIdentifier(n.Name)
+    case *ast.Literal:
+        return latexValue(formatValue(n))
+    case *ast.Assignment:
+        return fmt.Sprintf("%s = %s", latexIdentifier(n.Target.Name), LaTeX(n.Value))
+    case *ast.Call:
+        return latexCall(n)
+    case *ast.Unary:
+        return latexOperator(n.Operator) + latexOperand(n.Operand, prefixBindingPower(operatorToken(n.Operator)), false)
+    case *ast.Binary:
+        return latexBinary(n)
+    default:
+        return "0"
+    }
+}
+
+// latexBinary returns the LaTeX form of an infix operator.
+func latexBinary(n *ast.Binary) string {
+    lbp, rbp := infixBindingPower(operatorToken(n.Operator))
+    switch n.Operator {
+    case "/":
+        return fmt.Sprintf("\\frac{%s}{%s}", LaTeX(n.Left), LaTeX(n.Right))
+    case "^":
+        base := LaTeX(n.Left)
+        if !isBase(n.Left) {
+            base = "\\left(" + base + "\\right)"
+        }
+        return fmt.Sprintf("%s^{%s}", base, LaTeX(n.Right))
+    case "*":
+        return fmt.Sprintf("%s \\cdot %s", latexOperand(n.Left, lbp, true), latexOperand(n.Right, rbp, false))
+    case "//":
+        return fmt.Sprintf("\\left\\lfloor \\frac{%s}{%s} \\right\\rfloor", LaTeX(n.Left), LaTeX(n.Right))
+    default:
+        return fmt.Sprintf("%s %s %s", latexOperand(n.Left, lbp, true), latexOperator(n.Operator), latexOperand(n.Right, rbp, false))
+    }
+}
+
+// latexOperator returns the LaTeX form of an operator.
+func latexOperator(operator string) string {
+    if s, ok := latexOperators[operator]; ok {
+        return s
+    }
+    return operator
+}
+
+// latexOperand returns the LaTeX form of an operand, wrapped in parentheses if the binding power of its operator requires.
+func latexOperand(n ast.Node, bp int, left bool) string {
+    lbp, rbp := layoutBindingPowers(n)
+    s := LaTeX(n)
+    if needsParentheses(lbp, rbp, bp, left) {
+        return "\\left(" + s + "\\right)"
+    }
+    return s
+}
+
+// latexIdentifier returns the LaTeX form of an identifier.
+// Greek letters are symbols, other names longer than a letter are upright, with underscores escaped so they aren't subscripts.
+func latexIdentifier(name string) string {
+    switch {
+    case greekLetters[name] != "":
+        return "\\" + name
+    case len(name) == 1 && name != "_":
+        return name
+    default:
+        return "\\mathrm{" + strings.ReplaceAll(name, "_", "\\_") + "}"
+    }
+}
+
+// latexValue returns the LaTeX form of the text of a value, where rational numbers like '-1/3' are fractions
+// and exponents are powers of ten, like '6.02 \times 10^{23}' for '6.02E23'.
+func latexValue(s string) string {
+    if isFraction(s) {
+        sign, rest := splitSign(s)
+        numerator, denominator, _ := strings.Cut(rest, "/")
+        return fmt.Sprintf("%s\\frac{%s}{%s}", sign, numerator, denominator)
+    }
+    // Complex numbers like '1e-05 + 2i' have an exponent in either part.
+    fields := strings.Fields(s)
+    for i, field := range fields {
+        imaginary := strings.HasSuffix(field, "i")
+        mantissa, exponent, ok := splitExponent(strings.TrimSuffix(field, "i"))
+        if !ok {
+            continue
+        }
+        fields[i] = fmt.Sprintf("%s \\times 10^{%s}", mantissa, exponent)
+        if imaginary {
+            fields[i] += "i"
+        }
+    }
+    return strings.Join(fields, " ")
+}
+
+// latexCall returns the LaTeX form of a call.
+func latexCall(n *ast.Call) string {
+    args := make([]string, len(n.Args))
+    for i, arg := range n.Args {
+        args[i] = LaTeX(arg)
+    }
+    arg := strings.Join(args, ", ")
+
+    switch n.Name {
+    case "sqrt":
+        return "\\sqrt{" + arg + "}"
+    case "cbrt":
+        return "\\sqrt[3]{" + arg + "}"
+    case "abs":
+        return "\\left|" + arg + "\\right|"
+    case "floor":
+        return "\\left\\lfloor " + arg + " \\right\\rfloor"
+    case "ceil":
+        return "\\left\\lceil " + arg + " \\right\\rceil"
+    case "log10":
+        return "\\log_{10}\\left(" + arg + "\\right)"
+    case "log2":
+        return "\\log_{2}\\left(" + arg + "\\right)"
+    }
+    if name, ok := mathFunctions[n.Name]; ok {
+        return "\\" + name + "\\left(" + arg + "\\right)"
+    }
+    return "\\operatorname{" + n.Name + "}\\left(" + arg + "\\right)"
+}
+
+// layoutBindingPowers returns the binding powers of a node in the two-dimensional layouts of LaTeX and MathML.
+// Fractions, also the floored ones in brackets, are bound on neither side, and superscripts only on the left, where the base is.
+func layoutBindingPowers(n ast.Node) (int, int) {
+    switch n := n.(type) {
+    case *ast.Literal:
+        s := formatValue(n)
+        if isFraction(s) {
+            // The sign of a fraction binds like a prefix minus, the fraction itself isn't bound.
+            sign, _ := splitSign(s)
+            return valueBindingPowers(sign)
+        }
+        return valueBindingPowers(s)
+    case *ast.Binary:
+        switch n.Operator {
+        case "/", "//":
+            return atomBindingPower, atomBindingPower
+        case "^":
+            lbp, _ := infixBindingPower(operatorToken(n.Operator))
+            return lbp, atomBindingPower
+        }
+    }
+    return bindingPowers(n)
+}
+
+// isBase checks whether a node can be the base of a superscript without parentheses.
+// Only identifiers, calls and unsigned real literals can, fractions and powers as bases would be ambiguous.
+func isBase(n ast.Node) bool {
+    switch n := n.(type) {
+    case *ast.Identifier, *ast.Call:
+        return true
+    case *ast.Literal:
+        // Imaginary literals are products, like '2i'.
+        // Numbers with an exponent are products too, like '6.02 \times 10^{23}'.
+        s := formatValue(n)
+        _, _, hasExponent := splitExponent(s)
+        return !strings.ContainsAny(s, "-+/ ") && !strings.HasSuffix(s, "i") && !hasExponent
+    default:
+        return false
+    }
+}
+
+// splitSign splits the text of a value into its leading minus sign, if any, and the rest.
+func splitSign(s string) (string, string) {
+    if strings.HasPrefix(s, "-") {
+        return "-", s[1:]
+    }
+    return "", s
+}
+
+// splitExponent splits the text of a decimal number like '6.02E23' or '1e-05' into its mantissa and its exponent,
+// the exponent without a plus sign or leading zeros.
+func splitExponent(s string) (string, string, bool) {
+    i := strings.IndexAny(s, "eE")
+    if i < 0 {
+        return s, "", false
+    }
+    sign, exponent := splitSign(strings.TrimPrefix(s[i+1:], "+"))
+    if trimmed := strings.TrimLeft(exponent, "0"); trimmed != "" {
+        exponent = trimmed
+    }
+    return s[:i], sign + exponent, true
+}
+
+// isFraction checks whether the text of a value is a rational number like '1/3' or '-1/3'.
+func isFraction(s string) bool {
+    return strings.Contains(s, "/") && !strings.Contains(s, " ")
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/lexer"
+    "LexicalCalculator/number"
+    "math/big"
+    "testing"
+)
+
+func TestLaTeX(t *testing.T) {
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        {input: "1/x + sqrt(x)^2", result: "\\frac{1}{x} + \\sqrt{x}^{2}"},
+        {input: "(1+2)*3", result: "\\left(1 + 2\\right) \\cdot 3"},
+        {input: "a-(b-c)", result: "a - \\left(b - c\\right)"},
+        {input: "-(x+1)^2", result: "-\\left(x + 1\\right)^{2}"},
+        {input: "(-x)^2", result: "\\left(-x\\right)^{2}"},
+        {input: "(a/b)^2", result: "\\left(\\frac{a}{b}\\right)^{2}"},
+        {input: "(a^b)^c", result: "\\left(a^{b}\\right)^{c}"},
+        {input: "a**b**c", result: "a^{b^{c}}"},
+        {input: "2^(x+1)", result: "2^{x + 1}"},
+        {input: "2i^2", result: "\\left(2i\\right)^{2}"},
+        {input: "-(a/b)", result: "-\\frac{a}{b}"},
+        {input: "cbrt(x)/(1+x)", result: "\\frac{\\sqrt[3]{x}}{1 + x}"},
+        {input: "y = pi * r^2", result: "y = \\pi \\cdot r^{2}"},
+        {input: "(x + 1) % 12 + x // 2 * 3", result: "\\left(x + 1\\right) \\bmod 12 + \\left\\lfloor \\frac{x}{2} \\right\\rfloor \\cdot 3"},
+        {input: "a mod (b rem c)", result: "a \\bmod \\left(b \\mathbin{\\mathrm{rem}} c\\right)"},
+        {input: "~x & 1 | y xor 2 << 3", result: "\\lnot x \\mathbin{\\&} 1 \\mathbin{|} y \\oplus 2 \\ll 3"},
+        {input: "2.50 * speed", result: "2.50 \\cdot \\mathrm{speed}"},
+        {input: "my_var + a_b_c", result: "\\mathrm{my\\_var} + \\mathrm{a\\_b\\_c}"},
+        {input: "_ * x", result: "\\mathrm{\\_} \\cdot x"},
+        {input: "asin(x) + log10(x)", result: "\\arcsin\\left(x\\right) + \\log_{10}\\left(x\\right)"},
+        {input: "abs(x) * floor(x)", result: "\\left|x\\right| \\cdot \\left\\lfloor x \\right\\rfloor"},
+        {input: "max(1, 2.50, x)", result: "\\max\\left(1, 2.50, x\\right)"},
+        {input: "1_000 * x", result: "1000 \\cdot x"},
+        {input: "0x1F + 0b1010 + 0o17", result: "31 + 10 + 15"},
+        {input: "6.02E23", result: "6.02 \\times 10^{23}"},
+        {input: "1e-05 * x", result: "1 \\times 10^{-5} \\cdot x"},
+        {input: "2.5e+06i", result: "2.5 \\times 10^{6}i"},
+        {input: "2j + 0x1fj", result: "2i + 31i"},
+        {input: "2.5e3j", result: "2.5 \\times 10^{3}i"},
+        {input: "1_0.0_5e1_0 ^ 2", result: "\\left(10.05 \\times 10^{10}\\right)^{2}"},
+        {input: "x ^ 1e3", result: "x^{1 \\times 10^{3}}"},
+        {input: "hypot(x, 2)*(a+b)", result: "\\operatorname{hypot}\\left(x, 2\\right) \\cdot \\left(a + b\\right)"},
+    }
+
+    for _, tc := range testCases {
+        n, err := New(lexer.New()).ParseEquation(tc.input)
+        if err != nil {
+            t.Errorf("Error parsing %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        if s := LaTeX(n); s != tc.result {
+            t.Errorf("Error rendering %s: expected %s, got %s.\n", tc.input, tc.result, s)
+        }
+    }
+}
+
+func TestLaTeX_Value(t *testing.T) {
+    value := func(n number.Number) ast.Node { return ast.NewLiteral(nil, n) }
+    x := ast.NewIdentifier(nil, "x")
+
+    testCases := []struct {
+        node   ast.Node
+        result string
+    }{
+        {node: ast.NewBinary(nil, "*", value(number.NewRat(big.NewRat(-1, 3))), x), result: "-\\frac{1}{3} \\cdot x"},
+        {node: ast.NewBinary(nil, "^", value(number.NewRat(big.NewRat(1, 3))), x), result: "\\left(\\frac{1}{3}\\right)^{x}"},
+        {node: ast.NewBinary(nil, "*", x, value(number.Complex(1-2i))), result: "x \\cdot \\left(1 - 2i\\right)"},
+        {node: ast.NewBinary(nil, "-", x, value(number.Float(-2.5e21))), result: "x - -2.5 \\times 10^{21}"},
+    }
+
+    for _, tc := range testCases {
+        if s := LaTeX(tc.node); s != tc.result {
+            t.Errorf("Error rendering %s: expected %s, got %s.\n", tc.node, tc.result, s)
+        }
+    }
+}
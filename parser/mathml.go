@@ -0,0 +1,189 @@
+package parser
+
+import (
+    "LexicalCalculator/ast"
+    "html"
+    "strings"
+)
+
+// mathmlOperators maps the operators written differently in mathematical notation to their symbols.
+var mathmlOperators = map[string]string{"%": "mod", "xor": "⊕", "<<": "≪", ">>": "≫", "~": "¬"}
+
+// MathML returns the Presentation MathML form of a tree, a math element like
+// '<math xmlns="http://www.w3.org/1998/Math/MathML"><mfrac><mn>1</mn><mi>x</mi></mfrac></math>' for '1 / x'.
+// The layout is the same as the one of LaTeX, divisions are fractions and powers are superscripts.
+func MathML(n ast.Node) string {
+    return `<math xmlns="http://www.w3.org/1998/Math/MathML">` + mathml(n) + "</math>"
+}
+
+// mathml returns the MathML element of a node.
+func mathml(n ast.Node) string {
+    switch n := n.(type) {
+    case *ast.Identifier:
+        return mathmlIdentifier(n.Name)
+    case *ast.Literal:
+        return mathmlValue(formatValue(n))
+    case *ast.Assignment:
+        return row(mathmlIdentifier(n.Target.Name), element("mo", "="), mathml(n.Value))
+    case *ast.Call:
+        return mathmlCall(n)
+    case *ast.Unary:
+        return row(element("mo", mathmlOperator(n.Operator)), mathmlOperand(n.Operand, prefixBindingPower(operatorToken(n.Operator)), false))
+    case *ast.Binary:
+        return mathmlBinary(n)
+    default:
+        return element("mn", "0")
+    }
+}
+
+// mathmlBinary returns the MathML element of an infix operator.
+func mathmlBinary(n *ast.Binary) string {
+    lbp, rbp := infixBindingPower(operatorToken(n.Operator))
+    switch n.Operator {
+    case "/":
+        return "<mfrac>" + mathml(n.Left) + mathml(n.Right) + "</mfrac>"
+    case "^":
+        base := mathml(n.Left)
+        if !isBase(n.Left) {
+            base = parenthesize(base)
+        }
+        return "<msup>" + base + mathml(n.Right) + "</msup>"
+    case "*":
+        return row(mathmlOperand(n.Left, lbp, true), element("mo", "⋅"), mathmlOperand(n.Right, rbp, false))
+    case "//":
+        return row(element("mo", "⌊"), "<mfrac>"+mathml(n.Left)+mathml(n.Right)+"</mfrac>", element("mo", "⌋"))
+    default:
+        return row(mathmlOperand(n.Left, lbp, true), element("mo", mathmlOperator(n.Operator)), mathmlOperand(n.Right, rbp, false))
+    }
+}
+
+// mathmlOperator returns the symbol of an operator.
+func mathmlOperator(operator string) string {
+    if symbol, ok := mathmlOperators[operator]; ok {
+        return symbol
+    }
+    return operator
+}
+
+// mathmlOperand returns the MathML element of an operand, wrapped in parentheses if the binding power of its operator requires.
+func mathmlOperand(n ast.Node, bp int, left bool) string {
+    lbp, rbp := layoutBindingPowers(n)
+    s := mathml(n)
+    if needsParentheses(lbp, rbp, bp, left) {
+        return parenthesize(s)
+    }
+    return s
+}
+
+// mathmlIdentifier returns the MathML element of an identifier, greek letters are symbols.
+func mathmlIdentifier(name string) string {
+    if symbol, ok := greekLetters[name]; ok {
+        return element("mi", symbol)
+    }
+    return element("mi", name)
+}
+
+// mathmlValue returns the MathML element of the text of a value,
+// which might be a complex number like '1 - 2i' or a rational number like '-1/3'.
+func mathmlValue(s string) string {
+    if fields := strings.Fields(s); len(fields) > 1 {
+        elements := make([]string, len(fields))
+        for i, field := range fields {
+            if field == "+" || field == "-" {
+                elements[i] = element("mo", field)
+            } else {
+                elements[i] = mathmlValue(field)
+            }
+        }
+        return row(elements...)
+    }
+
+    sign, rest := splitSign(s)
+    var number string
+    switch {
+    case isFraction(rest):
+        numerator, denominator, _ := strings.Cut(rest, "/")
+        number = "<mfrac>" + element("mn", numerator) + element("mn", denominator) + "</mfrac>"
+    case rest == "i":
+        number = element("mi", "i")
+    case strings.HasSuffix(rest, "i"):
+        // An imaginary literal is the invisible product of its coefficient and i.
+        number = row(mathmlNumber(strings.TrimSuffix(rest, "i")), element("mo", "\u2062"), element("mi", "i"))
+    default:
+        number = mathmlNumber(rest)
+    }
+    if sign != "" {
+        return row(element("mo", sign), number)
+    }
+    return number
+}
+
+// mathmlCall returns the MathML element of a call.
+func mathmlCall(n *ast.Call) string {
+    args := make([]string, 0, 2*len(n.Args))
+    for i, arg := range n.Args {
+        if i > 0 {
+            args = append(args, element("mo", ","))
+        }
+        args = append(args, mathml(arg))
+    }
+    arg := row(args...)
+
+    var name string
+    switch n.Name {
+    case "sqrt":
+        return "<msqrt>" + arg + "</msqrt>"
+    case "cbrt":
+        return "<mroot>" + arg + element("mn", "3") + "</mroot>"
+    case "abs":
+        return row(element("mo", "|"), arg, element("mo", "|"))
+    case "floor":
+        return row(element("mo", "⌊"), arg, element("mo", "⌋"))
+    case "ceil":
+        return row(element("mo", "⌈"), arg, element("mo", "⌉"))
+    case "log10":
+        name = "<msub>" + element("mi", "log") + element("mn", "10") + "</msub>"
+    case "log2":
+        name = "<msub>" + element("mi", "log") + element("mn", "2") + "</msub>"
+    default:
+        if conventional, ok := mathFunctions[n.Name]; ok {
+            name = element("mi", conventional)
+        } else {
+            name = element("mi", n.Name)
+        }
+    }
+    // The function is applied to its arguments by the invisible function application operator.
+    return row(name, element("mo", "\u2061"), parenthesize(arg))
+}
+
+// mathmlNumber returns the MathML element of an unsigned decimal number, where exponents are powers of ten,
+// like '6.02 × 10' with a superscript 23 for '6.02E23'.
+func mathmlNumber(s string) string {
+    mantissa, exponent, ok := splitExponent(s)
+    if !ok {
+        return element("mn", s)
+    }
+    power := element("mn", exponent)
+    if sign, rest := splitSign(exponent); sign != "" {
+        power = row(element("mo", sign), element("mn", rest))
+    }
+    return row(element("mn", mantissa), element("mo", "\u00d7"), "<msup>"+element("mn", "10")+power+"</msup>")
+}
+
+// element returns a MathML element of escaped text, like '<mn>1</mn>'.
+func element(tag string, text string) string {
+    return "<" + tag + ">" + html.EscapeString(text) + "</" + tag + ">"
+}
+
+// row returns a MathML row of elements, a single element doesn't need a row.
+func row(elements ...string) string {
+    if len(elements) == 1 {
+        return elements[0]
+    }
+    return "<mrow>" + strings.Join(elements, "") + "</mrow>"
+}
+
+// parenthesize returns a MathML row of an element in parentheses.
+func parenthesize(s string) string {
+    return row(element("mo", "("), s, element("mo", ")"))
+}
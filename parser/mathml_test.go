@@ -0,0 +1,69 @@
+package parser
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/lexer"
+    "LexicalCalculator/number"
+    "math/big"
+    "testing"
+)
+
+func TestMathML(t *testing.T) {
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        {input: "1/x + sqrt(x)^2", result: "<mrow><mfrac><mn>1</mn><mi>x</mi></mfrac><mo>+</mo><msup><msqrt><mi>x</mi></msqrt><mn>2</mn></msup></mrow>"},
+        {input: "(1+2)*3", result: "<mrow><mrow><mo>(</mo><mrow><mn>1</mn><mo>+</mo><mn>2</mn></mrow><mo>)</mo></mrow><mo>⋅</mo><mn>3</mn></mrow>"},
+        {input: "-(x+1)^2", result: "<mrow><mo>-</mo><msup><mrow><mo>(</mo><mrow><mi>x</mi><mo>+</mo><mn>1</mn></mrow><mo>)</mo></mrow><mn>2</mn></msup></mrow>"},
+        {input: "(a/b)^2", result: "<msup><mrow><mo>(</mo><mfrac><mi>a</mi><mi>b</mi></mfrac><mo>)</mo></mrow><mn>2</mn></msup>"},
+        {input: "y = pi * r^2", result: "<mrow><mi>y</mi><mo>=</mo><mrow><mi>π</mi><mo>⋅</mo><msup><mi>r</mi><mn>2</mn></msup></mrow></mrow>"},
+        {input: "2.50 * 2i", result: "<mrow><mn>2.50</mn><mo>⋅</mo><mrow><mn>2</mn><mo>\u2062</mo><mi>i</mi></mrow></mrow>"},
+        {input: "x // 2 rem 3", result: "<mrow><mrow><mo>⌊</mo><mfrac><mi>x</mi><mn>2</mn></mfrac><mo>⌋</mo></mrow><mo>rem</mo><mn>3</mn></mrow>"},
+        {input: "x % 7", result: "<mrow><mi>x</mi><mo>mod</mo><mn>7</mn></mrow>"},
+        {input: "~x & y >> 2", result: "<mrow><mrow><mo>¬</mo><mi>x</mi></mrow><mo>&amp;</mo><mrow><mi>y</mi><mo>≫</mo><mn>2</mn></mrow></mrow>"},
+        {input: "cbrt(x)", result: "<mroot><mi>x</mi><mn>3</mn></mroot>"},
+        {input: "asin(x)", result: "<mrow><mi>arcsin</mi><mo>\u2061</mo><mrow><mo>(</mo><mi>x</mi><mo>)</mo></mrow></mrow>"},
+        {input: "log2(x)", result: "<mrow><msub><mi>log</mi><mn>2</mn></msub><mo>\u2061</mo><mrow><mo>(</mo><mi>x</mi><mo>)</mo></mrow></mrow>"},
+        {input: "max(1, x)", result: "<mrow><mi>max</mi><mo>\u2061</mo><mrow><mo>(</mo><mrow><mn>1</mn><mo>,</mo><mi>x</mi></mrow><mo>)</mo></mrow></mrow>"},
+        {input: "abs(x)", result: "<mrow><mo>|</mo><mi>x</mi><mo>|</mo></mrow>"},
+        {input: "1_000 + 0x1F", result: "<mrow><mn>1000</mn><mo>+</mo><mn>31</mn></mrow>"},
+        {input: "0b1010 * 0o17", result: "<mrow><mn>10</mn><mo>⋅</mo><mn>15</mn></mrow>"},
+        {input: "6.02E23", result: "<mrow><mn>6.02</mn><mo>×</mo><msup><mn>10</mn><mn>23</mn></msup></mrow>"},
+        {input: "1e-05", result: "<mrow><mn>1</mn><mo>×</mo><msup><mn>10</mn><mrow><mo>-</mo><mn>5</mn></mrow></msup></mrow>"},
+        {input: "2e3i", result: "<mrow><mrow><mn>2</mn><mo>×</mo><msup><mn>10</mn><mn>3</mn></msup></mrow><mo>\u2062</mo><mi>i</mi></mrow>"},
+        {input: "2j", result: "<mrow><mn>2</mn><mo>\u2062</mo><mi>i</mi></mrow>"},
+        {input: "0x1fj", result: "<mrow><mn>31</mn><mo>\u2062</mo><mi>i</mi></mrow>"},
+    }
+
+    for _, tc := range testCases {
+        n, err := New(lexer.New()).ParseEquation(tc.input)
+        if err != nil {
+            t.Errorf("Error parsing %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        result := `<math xmlns="http://www.w3.org/1998/Math/MathML">` + tc.result + "</math>"
+        if s := MathML(n); s != result {
+            t.Errorf("Error rendering %s: expected %s, got %s.\n", tc.input, result, s)
+        }
+    }
+}
+
+func TestMathML_Value(t *testing.T) {
+    testCases := []struct {
+        value  number.Number
+        result string
+    }{
+        {value: number.NewRat(big.NewRat(-1, 3)), result: "<mrow><mo>-</mo><mfrac><mn>1</mn><mn>3</mn></mfrac></mrow>"},
+        {value: number.Complex(1 - 2i), result: "<mrow><mn>1</mn><mo>-</mo><mrow><mn>2</mn><mo>\u2062</mo><mi>i</mi></mrow></mrow>"},
+        {value: number.Float(-5), result: "<mrow><mo>-</mo><mn>5</mn></mrow>"},
+        {value: number.Float(-2.5e21), result: "<mrow><mo>-</mo><mrow><mn>2.5</mn><mo>×</mo><msup><mn>10</mn><mn>21</mn></msup></mrow></mrow>"},
+    }
+
+    for _, tc := range testCases {
+        node := ast.NewLiteral(nil, tc.value)
+        if s := mathml(node); s != tc.result {
+            t.Errorf("Error rendering %s: expected %s, got %s.\n", tc.value, tc.result, s)
+        }
+    }
+}
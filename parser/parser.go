@@ -3,9 +3,13 @@ package parser
 import (
     "LexicalCalculator/ast"
     "LexicalCalculator/lexer"
+    "LexicalCalculator/number"
     "LexicalCalculator/token"
     "errors"
+    "fmt"
+    "math/big"
     "strconv"
+    "strings"
 )
 
 var (
@@ -16,8 +20,6 @@ var (
 )
 
 var (
-    // operatorSet stores all operator type.
-    operatorSet               = map[string]struct{}{token.PLUS: {}, token.MINUS: {}, token.SLASH: {}, token.ASTERISK: {}, token.CIRCUMFLEX: {}}
     correspondingRightBracket = map[string]string{token.LPAREN: token.RPAREN, token.LSQBRACK: token.RSQBRACK, token.LCURBRACK: token.RCURBRACK}
     correspondingLeftBracket  = map[string]string{token.RPAREN: token.LPAREN, token.RSQBRACK: token.LSQBRACK, token.RCURBRACK: token.LCURBRACK}
 )
@@ -29,33 +31,108 @@ type Parser struct {
     currToken      *token.Token
     nextToken      *token.Token
     equationCursor int
-    result         float64
+    closingQuote   *token.Token
+    errors         ErrorList
+    backend        number.Backend
+    env            *ast.Environment
+    result         number.Number
+    // inArguments tells whether the parser is within the arguments of a function call, where commas separate the arguments.
+    inArguments bool
+    // freeVariables allows undefined variables, which are bound when the equation is evaluated.
+    freeVariables bool
 }
 
 // New creates a new instance of a Parser.
+// The parser evaluates equations with number.FloatBackend until another backend is set.
+// Each parser holds the variables of its own session.
 func New(l *lexer.Lexer) *Parser {
-    return &Parser{l: l}
+    p := &Parser{l: l, backend: number.FloatBackend, env: ast.NewEnvironment()}
+    p.ClearPreviousAns()
+    return p
 }
 
 // Evaluate takes input and calculates the result.
-func (p *Parser) Evaluate(input string) (float64, error) {
+// If the prompt is invalid, the returned error is an ErrorList holding every error found in the prompt.
+func (p *Parser) Evaluate(input string) (number.Number, error) {
     p.input(input)
     err := p.parsePrompt()
     if err != nil {
-        return 0, err
+        return nil, p.errors
     }
-    n, err := p.parseEquation(0)
+    return p.evaluate()
+}
+
+// EvaluateEquation calculates the result of a bare equation like '5 + 2 * 3', which isn't wrapped in a calc prompt.
+// The spans of the errors are located in the equation.
+func (p *Parser) EvaluateEquation(equation string) (number.Number, error) {
+    p.input(equation)
+    err := p.readEquation()
+    if err != nil {
+        return nil, p.errors
+    }
+    return p.evaluate()
+}
+
+// ParseEquation parses a bare equation into an ast.Node without evaluating it.
+// Undefined variables are allowed, they are free variables bound when the node is evaluated.
+// If the equation is invalid, the returned error is an ErrorList holding every error found in the equation.
+func (p *Parser) ParseEquation(equation string) (ast.Node, error) {
+    p.input(equation)
+    err := p.readEquation()
+    if err != nil {
+        return nil, p.errors
+    }
+    p.freeVariables = true
+    defer func() { p.freeVariables = false }()
+    n, err := p.parseStatement()
+    if err != nil {
+        return nil, p.errors
+    }
+    return n, nil
+}
+
+// evaluate parses the equation tokens of the input and calculates the result.
+func (p *Parser) evaluate() (number.Number, error) {
+    n, err := p.parseStatement()
     if err != nil {
-        return 0, err
+        return nil, p.errors
+    }
+    result, err := ast.EvaluateIn(n, p.env)
+    if err != nil {
+        return nil, err
     }
-    result, err := ast.Evaluate(n)
     p.result = result
-    return result, err
+    return result, nil
 }
 
 // ClearPreviousAns resets the stored result to 0.
 func (p *Parser) ClearPreviousAns() {
-    p.result = 0
+    p.result, _ = p.backend.Parse("0")
+}
+
+// Environment returns the variables of the session.
+func (p *Parser) Environment() *ast.Environment {
+    return p.env
+}
+
+// Backend returns the numeric backend equations are evaluated with.
+func (p *Parser) Backend() number.Backend {
+    return p.backend
+}
+
+// SetBackend sets the numeric backend equations are evaluated with.
+// The variables and constants are converted to the new backend, those the new backend can't represent are kept as they are.
+// The stored result is converted as well, it's reset to 0 if the new backend can't represent it.
+func (p *Parser) SetBackend(backend number.Backend) {
+    p.backend = backend
+    p.env.Convert(backend)
+
+    result, err := backend.Convert(p.result)
+    if err != nil {
+        p.ClearPreviousAns()
+        return
+    }
+    p.result = result
 }
 
 // input takes input data and send it to the lexer.
@@ -63,6 +140,9 @@ func (p *Parser) ClearPreviousAns() {
 func (p *Parser) input(data string) {
     p.root = nil
     p.equationCursor = 0
+    p.closingQuote = nil
+    p.errors = nil
+    p.inArguments = false
     p.l.Input(data)
 }
 
@@ -86,10 +166,10 @@ func (p *Parser) parsePrompt() error {
     firstToken := p.readNextToken()
     if firstToken.LexicalType == token.EOF {
         // This happens when the prompt is empty.
-        return ErrPrompt
+        return p.errorf(ErrPrompt, firstToken, "empty prompt, expected calc '<equation>'")
     }
     if firstToken.Literal != "calc" && firstToken.LexicalType != token.CALC {
-        return ErrPrompt
+        return p.errorf(ErrPrompt, firstToken, "unknown command '%s', expected 'calc'", firstToken.Literal)
     }
     // After checking, assign the first token to root.
     p.root.Token = firstToken
@@ -101,10 +181,10 @@ func (p *Parser) parsePrompt() error {
     nextToken := p.readNextToken()
     if nextToken.LexicalType == token.EOF {
         // This happens when there is no following string after calc.
-        return ErrPrompt
+        return p.errorf(ErrPrompt, nextToken, "missing equation after 'calc'")
     }
     if nextToken.Literal != "'" && nextToken.LexicalType != token.SINGLEQUOTE {
-        return ErrOpeningQuote
+        return p.errorf(ErrOpeningQuote, nextToken, "expected opening quote before %s", describe(nextToken))
     }
 
     // After checking, assign it to the nextToken.
@@ -113,8 +193,10 @@ func (p *Parser) parsePrompt() error {
     for {
         if p.nextToken.LexicalType == token.EOF {
             if p.currToken.LexicalType != token.SINGLEQUOTE {
-                return ErrClosingQuote
+                // The EOF token is located right after the end of the input, where the quote is missing.
+                return p.errorf(ErrClosingQuote, p.nextToken, "missing closing quote at the end of the equation")
             }
+            p.closingQuote = p.currToken
             break
         }
 
@@ -129,11 +211,23 @@ func (p *Parser) parsePrompt() error {
             p.root.EquationTokens = append(p.root.EquationTokens, p.currToken)
         }
     }
-    if !hasBalanceBrackets(p.root.EquationTokens) {
-        return ErrEquation
-    }
+    return p.checkBrackets()
+}
 
-    return nil
+// readEquation reads the tokens of a bare equation until the end of the input.
+func (p *Parser) readEquation() error {
+    p.root = new(ast.Root)
+    p.root.EquationTokens = make([]*token.Token, 0)
+    for {
+        tok := p.readNextToken()
+        if tok.LexicalType == token.EOF {
+            // There is no closing quote, errors at the end of the equation are located at the end of the input instead.
+            p.closingQuote = tok
+            break
+        }
+        p.root.EquationTokens = append(p.root.EquationTokens, tok)
+    }
+    return p.checkBrackets()
 }
 
 // peekEquationToken retrieves the next token from equationTokens without advancing the cursor.
@@ -145,6 +239,24 @@ func (p *Parser) peekEquationToken() *token.Token {
     return p.root.EquationTokens[p.equationCursor]
 }
 
+// previousEquationToken retrieves the last token consumed from equationTokens.
+// It returns nil when the cursor is at the beginning of the token list.
+func (p *Parser) previousEquationToken() *token.Token {
+    if p.equationCursor == 0 {
+        return nil
+    }
+    return p.root.EquationTokens[p.equationCursor-1]
+}
+
+// peekNextEquationToken retrieves the token after the next token from equationTokens without advancing the cursor.
+// It returns nil if there is no such token.
+func (p *Parser) peekNextEquationToken() *token.Token {
+    if p.equationCursor+1 >= len(p.root.EquationTokens) {
+        return nil
+    }
+    return p.root.EquationTokens[p.equationCursor+1]
+}
+
 // nextEquationToken retrieves the next token from equationTokens list and advances the cursor.
 // If the cursor is at the end of the token list, it returns nil to indicate that there are no more tokens.
 func (p *Parser) nextEquationToken() *token.Token {
@@ -156,62 +268,98 @@ func (p *Parser) nextEquationToken() *token.Token {
     return tok
 }
 
-// parseEquation parses the equation stored in the Parser into an *ast.Node.
-func (p *Parser) parseEquation(minbp int) (*ast.Node, error) {
-    // Left hand side. Calling nextEquationToken returns the currToken the pointer is pointing at then advances it.
-    lhsTok := p.nextEquationToken()
+// parseStatement parses the equation stored in the Parser, which is either an assignment like 'rate = 0.07' or an expression.
+func (p *Parser) parseStatement() (ast.Node, error) {
+    tokens := p.root.EquationTokens
+    if len(tokens) < 2 || !isIdent(tokens[0]) || !isAssign(tokens[1]) {
+        return p.parseEquation(0)
+    }
+
+    identifier := ast.NewIdentifier(p.nextEquationToken(), tokens[0].Literal)
+    assignTok := p.nextEquationToken()
+    if p.env.IsConstant(identifier.Name) {
+        p.errorf(ErrEquation, identifier.Token, "cannot assign to constant '%s'", identifier.Name)
+    }
+
+    // The assigned expression can refer to the variable itself, like 'total = total + 1', which is evaluated before the assignment.
+    value, _ := p.parseEquation(0)
+    return ast.NewAssignment(assignTok, identifier, value), p.errors.Err()
+}
 
-    var lhs *ast.Node
+// parseEquation parses the equation stored in the Parser into an ast.Node.
+// Errors don't stop the parsing. Each error is recorded, the parser synchronizes on the next operator or closing bracket
+// and keeps going, so every independent error in the equation is found in one pass.
+// The returned error holds the errors found in this (sub-)equation, the returned node is incomplete if there are any.
+func (p *Parser) parseEquation(minbp int) (ast.Node, error) {
+    errCount := len(p.errors)
+
+    // Left hand side. Peek at the token first, since a token that isn't an operand shouldn't always be consumed.
+    lhsTok := p.peekEquationToken()
+
+    var lhs ast.Node
     switch {
-    case isInt(lhsTok):
-        lhsVal, _ := strconv.Atoi(lhsTok.Literal)
-        lhs = ast.New(lhsTok, float64(lhsVal), true, "", false, nil, nil)
-    case isFloat(lhsTok):
-        lhsVal, _ := strconv.ParseFloat(lhsTok.Literal, 32)
-        lhs = ast.New(lhsTok, lhsVal, true, "", false, nil, nil)
-
-    case isOperator(lhsTok):
-        rbp := prefixBindingPower(lhsTok)
-        // Scenario: Unknown operator, we shouldn't have operators other than '+' and '-'.
-        if rbp == 0 {
-            return nil, ErrEquation
+    case isInt(lhsTok), isFloat(lhsTok):
+        p.nextEquationToken()
+        lhsVal, err := p.backend.Parse(decimalLiteral(lhsTok.Literal))
+        if err != nil {
+            p.errorf(ErrEquation, lhsTok, "invalid %s number %s", p.backend, lhsTok.Literal)
         }
-
-        // Scenario: Something wrong happened when parsing a deeper node, like '5 + 6 *'.
-        rightChild, err := p.parseEquation(rbp)
+        lhs = ast.NewLiteral(lhsTok, lhsVal)
+    case isImag(lhsTok):
+        p.nextEquationToken()
+        // Imaginary numbers are complex no matter the backend, ast.Evaluate promotes the other operands.
+        imagVal, err := strconv.ParseFloat(decimalLiteral(lhsTok.Literal[:len(lhsTok.Literal)-1]), 64)
         if err != nil {
-            return nil, err
+            p.errorf(ErrEquation, lhsTok, "invalid imaginary number %s", lhsTok.Literal)
         }
+        lhs = ast.NewLiteral(lhsTok, number.Complex(complex(0, imagVal)))
+
+    case isOperator(lhsTok) && prefixBindingPower(lhsTok) != 0:
+        p.nextEquationToken()
+        rbp := prefixBindingPower(lhsTok)
+
+        // Scenario: Something wrong happened when parsing a deeper node, like '5 + 6 *'.
+        // The error is already recorded, keep the incomplete node and carry on.
+        rightChild, _ := p.parseEquation(rbp)
 
-        lhs = ast.New(lhsTok, 0, false, lhsTok.Literal, true, nil, rightChild)
+        lhs = ast.NewUnary(lhsTok, lhsTok.Literal, rightChild)
 
     case isLeftBracket(lhsTok):
-        var err error
+        p.nextEquationToken()
 
         // We know that an equation expression should exist within the bracket (no matter valid or not).
-        lhs, err = p.parseEquation(0)
-        if err != nil {
-            // Cases like [(12 + 3 * 6] + 1 might happen.
-            // We need to inherit the error from the last recursive call and break.
-            return nil, err
+        // Errors within the brackets are recorded, we still have to find the closing bracket.
+        // Brackets within the arguments of a call are a single argument, commas don't separate anything there.
+        inArguments := p.inArguments
+        p.inArguments = false
+        lhs, _ = p.parseEquation(0)
+        p.inArguments = inArguments
+
+        p.closeBracket(lhsTok)
+    case isAns(lhsTok):
+        p.nextEquationToken()
+        lhs = ast.NewLiteral(lhsTok, p.result)
+    case isIdent(lhsTok) && isLeftBracket(p.peekNextEquationToken()):
+        p.nextEquationToken()
+        lhs = p.parseCall(lhsTok)
+    case isIdent(lhsTok):
+        p.nextEquationToken()
+        if _, ok := p.env.Get(lhsTok.Literal); !ok && !p.freeVariables {
+            p.errorf(ErrEquation, lhsTok, "undefined variable '%s'", lhsTok.Literal)
         }
+        lhs = ast.NewIdentifier(lhsTok, lhsTok.Literal)
 
-        if p.peekEquationToken() == nil {
-            return nil, ErrEquation
-        } else if p.peekEquationToken().LexicalType != correspondingRightBracket[lhsTok.LexicalType] {
-            // Closing brackets doesn't match.
-            return nil, ErrEquation
-        } else {
-            // Consume the correct right parenthesis.
-            p.nextEquationToken()
-        }
-    case isAns(lhsTok):
-        lhs = ast.New(lhsTok, p.result, true, "", false, nil, nil)
+    case lhsTok != nil && !isOperator(lhsTok) && !isRightBracket(lhsTok) && !isComma(lhsTok):
+        // Scenario: An unknown type of token, like '5 + hello'.
+        // Skip it and whatever follows until the next operator or closing bracket.
+        p.nextEquationToken()
+        p.missingOperand(lhsTok)
+        p.synchronize()
 
     default:
-        // Scenario: Missing an integer, like '' or '5 + '.
-        // Or if the token we encounter is an unknown type.
-        return nil, ErrEquation
+        // Scenario: Missing an operand, like '' or '5 + ', or an operator that isn't a prefix operator, like '*5'.
+        // The token isn't consumed, so the operator, the comma or the closing bracket is still handled by the loop below or by the caller.
+        p.missingOperand(lhsTok)
     }
 
     for {
@@ -226,17 +374,38 @@ func (p *Parser) parseEquation(minbp int) (*ast.Node, error) {
             break
         }
 
-        // Scenario: Missing operator between integer tokens, like '5 25'.
-        // This also deals with something like '0)'.
+        if isComma(op) {
+            // A comma ends an argument of a function call, the call parses the next one.
+            if p.inArguments {
+                break
+            }
+            // Scenario: A comma outside of a function call, like '1, 2'.
+            p.errorf(ErrEquation, op, "',' can only separate the arguments of a function call")
+            p.nextEquationToken()
+            p.synchronize()
+            continue
+        }
+
+        // Scenario: Missing operator between integer tokens, like '5 25', or an unknown symbol, like '5 $'.
         if !isOperator(op) {
-            return nil, ErrEquation
+            if op.LexicalType == token.UNKNOWN {
+                p.errorf(ErrEquation, op, "unknown symbol '%s'", op.Literal)
+            } else if isAssign(op) {
+                p.errorf(ErrEquation, op, "'=' can only assign to a variable at the start of the equation")
+            } else {
+                p.errorf(ErrEquation, op, "missing operator between %s and %s", describe(p.previousEquationToken()), describe(op))
+            }
+            p.synchronize()
+            continue
         }
 
         // Get the binding power for the current operator.
         lbp, rbp := infixBindingPower(op)
         // Scenario: Unknown operator.
         if lbp == 0 || rbp == 0 {
-            return nil, ErrEquation
+            p.errorf(ErrEquation, op, "unknown operator %s", describe(op))
+            p.nextEquationToken()
+            continue
         }
 
         if lbp < minbp {
@@ -249,50 +418,201 @@ func (p *Parser) parseEquation(minbp int) (*ast.Node, error) {
         p.nextEquationToken()
 
         // Fetch the right hand side node recursively.
-        rhs, err := p.parseEquation(rbp)
-        if err != nil {
-            return nil, err
-        }
+        // Errors in the right hand side are recorded, keep the incomplete node and carry on.
+        rhs, _ := p.parseEquation(rbp)
 
         // Updates lhs for the next iteration.
         lhs = formEquation(op, lhs, rhs)
     }
-    return lhs, nil
+    return lhs, p.errors[errCount:].Err()
+}
+
+// parseCall parses the arguments of a call to the function named by the identifier token, like 'max(1, 2)'.
+// The cursor points at the opening bracket of the arguments.
+// Unknown functions and calls with the wrong number of arguments are recorded as errors.
+func (p *Parser) parseCall(nameTok *token.Token) *ast.Call {
+    openingTok := p.nextEquationToken()
+    args := make([]ast.Node, 0)
+
+    inArguments := p.inArguments
+    p.inArguments = true
+    // A function without arguments is called with empty brackets, like 'f()'.
+    if !isRightBracket(p.peekEquationToken()) {
+        for {
+            arg, _ := p.parseEquation(0)
+            args = append(args, arg)
+            if !isComma(p.peekEquationToken()) {
+                break
+            }
+            p.nextEquationToken()
+        }
+    }
+    p.inArguments = inArguments
+
+    p.closeBracket(openingTok)
+
+    if f, ok := p.env.Function(nameTok.Literal); !ok {
+        p.errorf(ErrEquation, nameTok, "unknown function '%s'", nameTok.Literal)
+    } else if f.CheckArity(len(args)) != nil {
+        p.errorf(ErrEquation, nameTok, "%s takes %s, got %d", f.Name, f.Arity(), len(args))
+    }
+    return ast.NewCall(nameTok, nameTok.Literal, args)
+}
+
+// closeBracket consumes the closing bracket of the opening bracket token.
+// The equation within the brackets ends either at a right bracket or at the end of the equation.
+func (p *Parser) closeBracket(openingTok *token.Token) {
+    closingTok := p.peekEquationToken()
+    if closingTok == nil {
+        p.errorf(ErrEquation, openingTok, "'%s' opened at column %d is never closed", openingTok.Literal, openingTok.Span.Column)
+        return
+    }
+    if closingTok.LexicalType != correspondingRightBracket[openingTok.LexicalType] {
+        // Closing brackets doesn't match, i.e. [(12 + 3 * 6] + 1.
+        // We take it as the closing bracket anyway to carry on.
+        p.errorf(ErrEquation, closingTok, "expected '%s' to close '%s' opened at column %d", correspondingRightBracket[openingTok.LexicalType], openingTok.Literal, openingTok.Span.Column)
+    }
+    // Consume the right parenthesis.
+    p.nextEquationToken()
+}
+
+// synchronize skips tokens until the next operator or closing bracket, where parsing can resume after an error.
+// Bracket groups are skipped as a whole, so the closing brackets within them don't stop the synchronization.
+func (p *Parser) synchronize() {
+    depth := 0
+    for {
+        tok := p.peekEquationToken()
+        switch {
+        case tok == nil:
+            return
+        case isLeftBracket(tok):
+            depth++
+        case isRightBracket(tok):
+            if depth == 0 {
+                return
+            }
+            depth--
+        case isOperator(tok) && depth == 0:
+            return
+        case isComma(tok) && depth == 0 && p.inArguments:
+            return
+        }
+        p.nextEquationToken()
+    }
 }
 
-// formEquation creates a new ast.Node representing an operator and its operands.
-func formEquation(op *token.Token, lhs *ast.Node, rhs *ast.Node) *ast.Node {
-    operatorNode := ast.New(op, 0, false, op.Literal, true, lhs, rhs)
-    return operatorNode
+// errorf records an Error located at the span of the offending token, with a formatted message.
+// A nil token means the equation ends unexpectedly, so the error is located at the closing quote instead.
+func (p *Parser) errorf(err error, tok *token.Token, format string, args ...any) *Error {
+    if tok == nil {
+        tok = p.closingQuote
+    }
+    e := &Error{Err: err, Message: fmt.Sprintf(format, args...)}
+    if tok != nil {
+        e.Span = tok.Span
+    }
+    p.errors = append(p.errors, e)
+    return e
+}
+
+// missingOperand records the error of a token found where an operand is expected.
+func (p *Parser) missingOperand(tok *token.Token) *Error {
+    prev := p.previousEquationToken()
+    switch {
+    case tok == nil && prev == nil:
+        return p.errorf(ErrEquation, nil, "empty equation")
+    case tok == nil:
+        return p.errorf(ErrEquation, nil, "missing operand after %s", describe(prev))
+    case tok.LexicalType == token.UNKNOWN:
+        return p.errorf(ErrEquation, tok, "unknown symbol '%s'", tok.Literal)
+    case isOperator(tok), isRightBracket(tok), isComma(tok):
+        return p.errorf(ErrEquation, tok, "missing operand before %s", describe(tok))
+    default:
+        return p.errorf(ErrEquation, tok, "unexpected %s", describe(tok))
+    }
+}
+
+// decimalLiteral returns the decimal form of a number literal, since backends only parse decimal numbers.
+// Digit separators are removed, like in '1_000', and integers with a base prefix, like '0xff', are converted into decimal.
+// The conversion is exact, so integers of any size keep all of their digits.
+func decimalLiteral(literal string) string {
+    literal = strings.ReplaceAll(literal, "_", "")
+    if len(literal) > 2 && literal[0] == '0' && strings.ContainsRune("xXbBoO", rune(literal[1])) {
+        if i, ok := new(big.Int).SetString(literal, 0); ok {
+            return i.String()
+        }
+    }
+    return literal
+}
+
+// formEquation creates a new ast.Binary representing an operator and its operands.
+func formEquation(op *token.Token, lhs ast.Node, rhs ast.Node) *ast.Binary {
+    // Aliases like '**' have the lexical type of the operator they stand for.
+    return ast.NewBinary(op, op.LexicalType, lhs, rhs)
+}
+
+// associativity is the side chained operators of the same precedence group to, like '2 ^ 3 ^ 2' to '2 ^ (3 ^ 2)'.
+type associativity int
+
+const (
+    leftAssociative associativity = iota
+    rightAssociative
+)
+
+// infixOperator is an entry of the operator table.
+type infixOperator struct {
+    precedence    int
+    associativity associativity
 }
 
-// infixBindingPower returns the left and right binding powers for different operators.
+// infixOperators is the operator table of the infix operators, by their lexical type.
+// Higher precedences bind tighter. The bitwise operators bind looser than the arithmetic ones, like in C and Python,
+// so '1 << 2 + 3' is '1 << (2 + 3)' and 'x & 0xff | y' is '(x & 0xff) | y'.
+var infixOperators = map[string]infixOperator{
+    token.PIPE:       {precedence: 1, associativity: leftAssociative},
+    token.XOR:        {precedence: 2, associativity: leftAssociative},
+    token.AMPERSAND:  {precedence: 3, associativity: leftAssociative},
+    token.SHL:        {precedence: 4, associativity: leftAssociative},
+    token.SHR:        {precedence: 4, associativity: leftAssociative},
+    token.PLUS:       {precedence: 5, associativity: leftAssociative},
+    token.MINUS:      {precedence: 5, associativity: leftAssociative},
+    token.ASTERISK:   {precedence: 6, associativity: leftAssociative},
+    token.SLASH:      {precedence: 6, associativity: leftAssociative},
+    token.DBLSLASH:   {precedence: 6, associativity: leftAssociative},
+    token.PERCENT:    {precedence: 6, associativity: leftAssociative},
+    token.REM:        {precedence: 6, associativity: leftAssociative},
+    token.CIRCUMFLEX: {precedence: 8, associativity: rightAssociative},
+}
+
+// prefixOperators is the operator table of the prefix operators, by their lexical type.
+// They bind tighter than '*' and '/' but looser than '^', so '-1 ^ 4' is '-(1 ^ 4)'.
+var prefixOperators = map[string]int{
+    token.PLUS:  7,
+    token.MINUS: 7,
+    token.TILDE: 7,
+}
+
+// infixBindingPower returns the left and right binding powers for different operators, derived from the operator table.
+// Left associative operators bind tighter on the right, so the left operator of a chain takes the operand between them,
+// right associative operators bind tighter on the left.
 func infixBindingPower(operatorToken *token.Token) (int, int) {
-    switch operatorToken.LexicalType {
-    case token.PLUS:
-        return 1, 2
-    case token.MINUS:
-        return 1, 2
-    case token.ASTERISK:
-        return 3, 4
-    case token.SLASH:
-        return 3, 4
-    case token.CIRCUMFLEX:
-        return 6, 7
-    }
-    return 0, 0
-}
-
-// prefixBindingPower returns the right binding powers for prefix operators like '+' and '-'.
+    operator, ok := infixOperators[operatorToken.LexicalType]
+    if !ok {
+        return 0, 0
+    }
+    if operator.associativity == rightAssociative {
+        return 2 * operator.precedence, 2*operator.precedence - 1
+    }
+    return 2*operator.precedence - 1, 2 * operator.precedence
+}
+
+// prefixBindingPower returns the right binding powers for prefix operators like '+' and '-', derived from the operator table.
 func prefixBindingPower(operatorToken *token.Token) int {
-    switch operatorToken.LexicalType {
-    case token.PLUS:
-        return 5
-    case token.MINUS:
-        return 5
-    default:
+    precedence, ok := prefixOperators[operatorToken.LexicalType]
+    if !ok {
         return 0
     }
+    return 2*precedence - 1
 }
 
 // isInt checks whether a token is an integer.
@@ -311,11 +631,42 @@ func isFloat(tok *token.Token) bool {
     return false
 }
 
+// isImag checks whether a token is an imaginary number.
+func isImag(tok *token.Token) bool {
+    if tok != nil {
+        return tok.LexicalType == token.IMAG
+    }
+    return false
+}
+
+// isIdent checks whether a token is an identifier.
+func isIdent(tok *token.Token) bool {
+    if tok != nil {
+        return tok.LexicalType == token.IDENT
+    }
+    return false
+}
+
+// isAssign checks whether a token is an assignment.
+func isAssign(tok *token.Token) bool {
+    if tok != nil {
+        return tok.LexicalType == token.ASSIGN
+    }
+    return false
+}
+
+// isComma checks whether a token is a comma.
+func isComma(tok *token.Token) bool {
+    if tok != nil {
+        return tok.LexicalType == token.COMMA
+    }
+    return false
+}
+
 // isOperator checks whether a token is an operator.
 func isOperator(tok *token.Token) bool {
     if tok != nil {
-        _, ok := operatorSet[tok.LexicalType]
-        return ok
+        return token.IsPrefixOperator(tok.LexicalType) || token.IsInfixOperator(tok.LexicalType)
     }
     return false
 }
@@ -347,14 +698,12 @@ func isAns(tok *token.Token) bool {
     return false
 }
 
-// hasBalanceBrackets checks whether the tokens has balances parentheses.
-func hasBalanceBrackets(tokens []*token.Token) bool {
+// checkBrackets checks whether the equation tokens have balanced brackets.
+// It records an error for every bracket that breaks the balance, and returns them.
+func (p *Parser) checkBrackets() error {
+    errCount := len(p.errors)
+    tokens := p.root.EquationTokens
     stack := make([]*token.Token, 0)
-    pop := func(stack *[]*token.Token) {
-        if len(*stack) != 0 {
-            *stack = (*stack)[0 : len(*stack)-1]
-        }
-    }
 
     // Check if the opening and closing parentheses matches.
     // Check invalid brackets like [(]).
@@ -364,18 +713,42 @@ func hasBalanceBrackets(tokens []*token.Token) bool {
         case isLeftBracket(tok):
             stack = append(stack, tok)
         case isRightBracket(tok):
-            // Check if the last token is the current tokens' corresponding left bracket, i.e. ()23 + 5 or 23 + 5().
-            if n > 0 && tokens[n-1].LexicalType != correspondingLeftBracket[tok.LexicalType] {
-                // If the stack length is 0, we have a redundant closing bracket.
+            // If the stack length is 0, we have a redundant closing bracket. Skip it.
+            if len(stack) == 0 {
+                p.errorf(ErrEquation, tok, "unmatched '%s'", tok.Literal)
+                continue
+            }
+
+            opening := stack[len(stack)-1]
+            switch {
+            case n > 0 && tokens[n-1] == opening && opening.LexicalType == correspondingLeftBracket[tok.LexicalType] && !(n > 1 && isIdent(tokens[n-2])):
+                // The last token is the current tokens' corresponding left bracket, i.e. ()23 + 5 or 23 + 5().
+                // Empty brackets are fine after a function name, where they call a function without arguments.
+                p.errorf(ErrEquation, tok, "empty brackets '%s%s'", opening.Literal, tok.Literal)
+            case opening.LexicalType != correspondingLeftBracket[tok.LexicalType]:
                 // If the type of last token in the stack isn't the corresponding left bracket, we have invalid bracket grammar, i.e. [(]).
-                if len(stack) == 0 || stack[len(stack)-1].LexicalType != correspondingLeftBracket[tok.LexicalType] {
-                    return false
-                }
-                pop(&stack)
+                // Take it as the closing bracket anyway, so the following brackets are still checked.
+                p.errorf(ErrEquation, tok, "expected '%s' to close '%s' opened at column %d", correspondingRightBracket[opening.LexicalType], opening.Literal, opening.Span.Column)
             }
+            stack = stack[:len(stack)-1]
         }
     }
 
     // If the length of the stack isn't 0, we have opening brackets that aren't closed.
-    return len(stack) == 0
+    for _, opening := range stack {
+        p.errorf(ErrEquation, opening, "'%s' opened at column %d is never closed", opening.Literal, opening.Span.Column)
+    }
+    return p.errors[errCount:].Err()
+}
+
+// describe returns how a token is referred to in error messages.
+// Numbers are written as they are, other tokens are quoted.
+func describe(tok *token.Token) string {
+    if tok == nil {
+        return "the end of the equation"
+    }
+    if isInt(tok) || isFloat(tok) || isImag(tok) {
+        return tok.Literal
+    }
+    return fmt.Sprintf("'%s'", tok.Literal)
 }
@@ -3,9 +3,14 @@ package parser
 import (
     "LexicalCalculator/ast"
     "LexicalCalculator/lexer"
+    "LexicalCalculator/number"
     "LexicalCalculator/support"
     "LexicalCalculator/token"
+    "LexicalCalculator/vm"
     "errors"
+    "fmt"
+    "reflect"
+    "strings"
     "testing"
 )
 
@@ -50,6 +55,14 @@ func TestParser_Parse(t *testing.T) {
             {input: "calc '-5 +'", err: ErrEquation},
             // Zero division.
             {input: "calc '1 / 0'", err: ast.ErrZeroDivision},
+            {input: "calc '1 % 0'", err: ast.ErrZeroDivision},
+            {input: "calc '1 // 0'", err: ast.ErrZeroDivision},
+            {input: "calc '1 mod (2 - 2)'", err: ast.ErrZeroDivision},
+            {input: "calc '1 rem 0.0'", err: ast.ErrZeroDivision},
+            // Bitwise operators on non-integers.
+            {input: "calc '2.5 & 1'", err: ast.ErrNotAnInteger},
+            {input: "calc '~0.5'", err: ast.ErrNotAnInteger},
+            {input: "calc '1 << -1'", err: number.ErrShiftCount},
         }
 
         for testNum, tc := range testCases {
@@ -72,6 +85,11 @@ func TestParser_Parse(t *testing.T) {
                 if !errors.Is(err, tc.err) {
                     t.Errorf("error evaluating incorrect equation: expected error %s, got error %s.\n", tc.err, err)
                 }
+
+                code, _ := vm.Compile(n, nil)
+                if _, err = vm.Run(code, nil, make([]float64, code.StackSize)); !errors.Is(err, tc.err) {
+                    t.Errorf("error running the bytecode of incorrect equation: expected error %s, got error %s.\n", tc.err, err)
+                }
             }
         }
     })
@@ -113,6 +131,32 @@ func TestParser_Parse(t *testing.T) {
             {input: "calc '5 + - 5'", tokens: 4, result: 0},
             {input: "calc '-1 ^ 4'", tokens: 4, result: -1},
             {input: "calc '2 ^ -1'", tokens: 4, result: 0.5},
+            {input: "calc '-2 ** 2'", tokens: 4, result: -4},
+
+            // Modulo, floor division and remainder.
+            {input: "calc '17 % 5'", tokens: 3, result: 2},
+            {input: "calc '17 // 5'", tokens: 3, result: 3},
+            {input: "calc '-17 mod 5'", tokens: 4, result: 3},
+            {input: "calc '-17 rem 5'", tokens: 4, result: -2},
+            {input: "calc '-17 // 5'", tokens: 4, result: -4},
+            {input: "calc '17 % -5'", tokens: 4, result: -3},
+            {input: "calc '1 + 17 % 5 * 2'", tokens: 7, result: 5},
+            {input: "calc '7.5 % 2'", tokens: 3, result: 1.5},
+
+            // Bitwise operators bind looser than the arithmetic ones.
+            {input: "calc '12 & 10 | 1'", tokens: 5, result: 9},
+            {input: "calc '12 xor 10 & 8'", tokens: 5, result: 4},
+            {input: "calc '1 << 2 + 3'", tokens: 5, result: 32},
+            {input: "calc '-16 >> 2'", tokens: 4, result: -4},
+            {input: "calc '~5 + 1'", tokens: 4, result: -5},
+            {input: "calc '~-3 & 7'", tokens: 5, result: 2},
+
+            // Powers are right associative.
+            {input: "calc '2 ^ 3 ^ 2'", tokens: 5, result: 512},
+            {input: "calc '2 ** 3 ** 2'", tokens: 5, result: 512},
+            {input: "calc '(2 ^ 3) ^ 2'", tokens: 7, result: 64},
+            {input: "calc '2 ^ 3 ** 0 ^ 2'", tokens: 7, result: 2},
+            {input: "calc '2 ^ -1 ^ 2'", tokens: 6, result: 0.5},
 
             // Brackets.
             {input: "calc '(0)'", tokens: 3, result: 0},
@@ -173,10 +217,418 @@ func TestParser_Parse(t *testing.T) {
                 }
                 p.result = val
                 // Setting epsilon as accuracy.
-                if !support.AlmostEqual(val, float64(tc.result), 0.0001) {
-                    t.Errorf("error calculated value: expected %f, got %f.\n", tc.result, val)
+                if !support.AlmostEqual(val.Float64(), float64(tc.result), 0.0001) {
+                    t.Errorf("error calculated value: expected %f, got %s.\n", tc.result, val)
+                }
+
+                // The bytecode of the tree has the same result.
+                code, err := vm.Compile(n, nil)
+                if err != nil {
+                    t.Errorf("error compiling %s: got error %s.\n", tc.input, err)
+                    continue
+                }
+                vmVal, err := vm.Run(code, nil, make([]float64, code.StackSize))
+                if err != nil || vmVal != val.Float64() {
+                    t.Errorf("error running the bytecode of %s: expected %f, got %f, error %v.\n", tc.input, val.Float64(), vmVal, err)
                 }
             }
         }
     })
 }
+
+func TestParser_Evaluate_Error(t *testing.T) {
+    l := lexer.New()
+    p := New(l)
+
+    testCases := []struct {
+        input   string
+        err     error
+        message string
+        span    token.Span
+    }{
+        {input: "", err: ErrPrompt, message: "empty prompt, expected calc '<equation>'", span: token.Span{Offset: 0, Line: 1, Column: 1, Length: 0}},
+        {input: "caldc '5 + 5'", err: ErrPrompt, message: "unknown command 'caldc', expected 'calc'", span: token.Span{Offset: 0, Line: 1, Column: 1, Length: 5}},
+        {input: "calc", err: ErrPrompt, message: "missing equation after 'calc'", span: token.Span{Offset: 4, Line: 1, Column: 5, Length: 0}},
+        {input: "calc 5 + 5'", err: ErrOpeningQuote, message: "expected opening quote before 5", span: token.Span{Offset: 5, Line: 1, Column: 6, Length: 1}},
+        {input: "calc '5 + 5", err: ErrClosingQuote, message: "missing closing quote at the end of the equation", span: token.Span{Offset: 11, Line: 1, Column: 12, Length: 0}},
+        {input: "calc ''", err: ErrEquation, message: "empty equation", span: token.Span{Offset: 6, Line: 1, Column: 7, Length: 1}},
+        {input: "calc '5 25'", err: ErrEquation, message: "missing operator between 5 and 25", span: token.Span{Offset: 8, Line: 1, Column: 9, Length: 2}},
+        {input: "calc '5 +'", err: ErrEquation, message: "missing operand after '+'", span: token.Span{Offset: 9, Line: 1, Column: 10, Length: 1}},
+        {input: "calc '*5'", err: ErrEquation, message: "missing operand before '*'", span: token.Span{Offset: 6, Line: 1, Column: 7, Length: 1}},
+        {input: "calc '[(1]) * 2'", err: ErrEquation, message: "expected ')' to close '(' opened at column 8", span: token.Span{Offset: 9, Line: 1, Column: 10, Length: 1}},
+        {input: "calc '[1 + 2) * 3]'", err: ErrEquation, message: "expected ']' to close '[' opened at column 7", span: token.Span{Offset: 12, Line: 1, Column: 13, Length: 1}},
+        {input: "calc '(1 + 2'", err: ErrEquation, message: "'(' opened at column 7 is never closed", span: token.Span{Offset: 6, Line: 1, Column: 7, Length: 1}},
+        {input: "calc '1 + 2)'", err: ErrEquation, message: "unmatched ')'", span: token.Span{Offset: 11, Line: 1, Column: 12, Length: 1}},
+        {input: "calc '()1 * 2'", err: ErrEquation, message: "empty brackets '()'", span: token.Span{Offset: 7, Line: 1, Column: 8, Length: 1}},
+        {input: "calc '1 + $'", err: ErrEquation, message: "unknown symbol '$'", span: token.Span{Offset: 10, Line: 1, Column: 11, Length: 1}},
+        {input: "calc '1 < 2'", err: ErrEquation, message: "unknown symbol '<'", span: token.Span{Offset: 8, Line: 1, Column: 9, Length: 1}},
+        {input: "calc '1 + hello'", err: ErrEquation, message: "undefined variable 'hello'", span: token.Span{Offset: 10, Line: 1, Column: 11, Length: 5}},
+        {input: "calc '1 = 2'", err: ErrEquation, message: "'=' can only assign to a variable at the start of the equation", span: token.Span{Offset: 8, Line: 1, Column: 9, Length: 1}},
+        {input: "calc 'foo(2)'", err: ErrEquation, message: "unknown function 'foo'", span: token.Span{Offset: 6, Line: 1, Column: 7, Length: 3}},
+        {input: "calc 'sqrt(1, 2)'", err: ErrEquation, message: "sqrt takes 1 argument, got 2", span: token.Span{Offset: 6, Line: 1, Column: 7, Length: 4}},
+        {input: "calc 'hypot()'", err: ErrEquation, message: "hypot takes 2 arguments, got 0", span: token.Span{Offset: 6, Line: 1, Column: 7, Length: 5}},
+        {input: "calc 'max(1, )'", err: ErrEquation, message: "missing operand before ')'", span: token.Span{Offset: 13, Line: 1, Column: 14, Length: 1}},
+        {input: "calc '1, 2'", err: ErrEquation, message: "',' can only separate the arguments of a function call", span: token.Span{Offset: 7, Line: 1, Column: 8, Length: 1}},
+        {input: "calc 'max((1, 2), 3)'", err: ErrEquation, message: "',' can only separate the arguments of a function call", span: token.Span{Offset: 12, Line: 1, Column: 13, Length: 1}},
+    }
+
+    for _, tc := range testCases {
+        _, err := p.Evaluate(tc.input)
+        if !errors.Is(err, tc.err) {
+            t.Errorf("error evaluating %q: expected error %s, got error %s.\n", tc.input, tc.err, err)
+        }
+
+        // Only the first error is checked here, other errors found in the same prompt are checked in TestParser_Evaluate_ErrorRecovery.
+        var errList ErrorList
+        if !errors.As(err, &errList) || len(errList) == 0 {
+            t.Errorf("error evaluating %q: expected an ErrorList, got %v.\n", tc.input, err)
+            continue
+        }
+        if errList[0].Message != tc.message {
+            t.Errorf("error message of %q: expected %q, got %q.\n", tc.input, tc.message, errList[0].Message)
+        }
+        if errList[0].Span != tc.span {
+            t.Errorf("error span of %q: expected %+v, got %+v.\n", tc.input, tc.span, errList[0].Span)
+        }
+    }
+}
+
+func TestParser_Evaluate_ErrorRecovery(t *testing.T) {
+    l := lexer.New()
+    p := New(l)
+
+    testCases := []struct {
+        input    string
+        messages []string
+    }{
+        {
+            input:    "calc '5 25 + 3'",
+            messages: []string{"missing operator between 5 and 25"},
+        },
+        {
+            input:    "calc '5 25 + * 3 4'",
+            messages: []string{"missing operator between 5 and 25", "missing operand before '*'", "missing operator between 3 and 4"},
+        },
+        {
+            input:    "calc '1 + $ * 2 + (3 #) - 4 +'",
+            messages: []string{"unknown symbol '$'", "unknown symbol '#'", "missing operand after '+'"},
+        },
+        {
+            input:    "calc '(1 2) * [3 +] * {4 (5)}'",
+            messages: []string{"missing operator between 1 and 2", "missing operand before ']'", "missing operator between 4 and '('"},
+        },
+        {
+            input:    "calc '[(1]) * 2'",
+            messages: []string{"expected ')' to close '(' opened at column 8", "expected ']' to close '[' opened at column 7"},
+        },
+        {
+            input:    "calc ') + (1 + 2] + (3'",
+            messages: []string{"unmatched ')'", "expected ')' to close '(' opened at column 11", "'(' opened at column 21 is never closed"},
+        },
+    }
+
+    for _, tc := range testCases {
+        _, err := p.Evaluate(tc.input)
+
+        var errList ErrorList
+        if !errors.As(err, &errList) {
+            t.Errorf("error evaluating %q: expected an ErrorList, got %v.\n", tc.input, err)
+            continue
+        }
+        if len(errList) != len(tc.messages) {
+            t.Errorf("error evaluating %q: expected %d errors, got %d: %v.\n", tc.input, len(tc.messages), len(errList), errList)
+            continue
+        }
+        for n, message := range tc.messages {
+            if errList[n].Message != message {
+                t.Errorf("error message %d of %q: expected %q, got %q.\n", n, tc.input, message, errList[n].Message)
+            }
+        }
+    }
+}
+
+func TestParser_SetBackend(t *testing.T) {
+    l := lexer.New()
+    p := New(l)
+
+    rational, _ := number.NewBackend(number.RATIONAL, 0)
+    bigFloat, _ := number.NewBackend(number.BIGFLOAT, 0)
+
+    testCases := []struct {
+        backend number.Backend
+        input   string
+        result  string
+    }{
+        {backend: number.FloatBackend, input: "calc '2.2 * 3'", result: "6.6000000000000005"},
+        {backend: rational, input: "calc 'ans'", result: "6.6000000000000005"},
+        {backend: rational, input: "calc '2.2 * 3'", result: "6.6"},
+        {backend: rational, input: "calc '0.1 + 0.2'", result: "0.3"},
+        {backend: rational, input: "calc '2 ^ 200'", result: "1606938044258990275541962092341162602522202993782792835301376"},
+        {backend: rational, input: "calc '(1 / 3) * 3'", result: "1"},
+        {backend: bigFloat, input: "calc 'ans'", result: "1"},
+        {backend: bigFloat, input: "calc '0.1 + 0.2'", result: "0.3"},
+        {backend: bigFloat, input: "calc '-2 ^ 3'", result: "-8"},
+
+        // Literals are converted exactly, no matter their form.
+        {backend: number.FloatBackend, input: "calc '0xFF + 0b1010 + 0o17 + 1_000'", result: "1280"},
+        {backend: number.FloatBackend, input: "calc '6.02E23'", result: "6.02e+23"},
+        {backend: number.FloatBackend, input: "calc '1e-9'", result: "1e-09"},
+        {backend: number.FloatBackend, input: "calc '0.1e1 + 017'", result: "18"},
+        {backend: rational, input: "calc '0xFFFF_FFFF_FFFF_FFFF_FF + 1'", result: "4722366482869645213696"},
+        {backend: rational, input: "calc '1.5e-3'", result: "0.0015"},
+        {backend: bigFloat, input: "calc '1.000_000_000_000_000_000_001e3'", result: "1000.000000000000000001"},
+        {backend: number.IntBackend, input: "calc '0x7fff_ffff_ffff_ffff'", result: "9223372036854775807"},
+        {backend: number.IntBackend, input: "calc '6.02E15 // 1e12'", result: "6020"},
+        {backend: number.IntBackend, input: "calc '~0x7fff_ffff_ffff_ffff'", result: "-9223372036854775808"},
+        {backend: number.IntBackend, input: "calc '-1 << 63'", result: "-9223372036854775808"},
+        {backend: number.ComplexBackend, input: "calc '0x10i + 1e1'", result: "10 + 16i"},
+    }
+
+    for _, tc := range testCases {
+        p.SetBackend(tc.backend)
+        val, err := p.Evaluate(tc.input)
+        if err != nil {
+            t.Errorf("Error evaluating %s with %s backend: got error %v.\n", tc.input, tc.backend, err)
+            continue
+        }
+        if val.String() != tc.result {
+            t.Errorf("Error evaluating %s with %s backend: expected %s, got %s.\n", tc.input, tc.backend, tc.result, val)
+        }
+    }
+}
+
+func TestParser_SetBackend_Overflow(t *testing.T) {
+    p := New(lexer.New())
+    bigFloat, _ := number.NewBackend(number.BIGFLOAT, 0)
+    p.SetBackend(bigFloat)
+
+    // Results beyond the exponent range of big.Float are errors instead of infinities, which big.Float panics on.
+    for _, input := range []string{
+        "calc '2 ^ 9223372036854775807'",
+        "calc '0 * (2 ^ 9223372036854775807)'",
+        "calc '(2 ^ 9223372036854775807) - (2 ^ 9223372036854775807)'",
+        "calc '2 ^ (-9223372036854775807 - 1)'",
+        "calc '1 % (2 ^ 9223372036854775807)'",
+        "calc '(2 ^ 9223372036854775807) % 3'",
+        "calc '(2 ^ 9223372036854775807) rem 3'",
+        "calc '(2 ^ 9223372036854775807) // 3'",
+    } {
+        if val, err := p.Evaluate(input); !errors.Is(err, number.ErrExponentTooLarge) {
+            t.Errorf("Error evaluating %s with bigfloat backend: expected error %s, got %v, error %v.\n", input, number.ErrExponentTooLarge, val, err)
+        }
+    }
+}
+
+func TestParser_Evaluate_Complex(t *testing.T) {
+    l := lexer.New()
+    p := New(l)
+
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        {input: "calc '4i'", result: "0 + 4i"},
+        {input: "calc '(3 + 4i) * (1 - 2i)'", result: "11 - 2i"},
+        {input: "calc '(3 + 4j) / (1 - 2j)'", result: "-1 + 2i"},
+        {input: "calc '-2.5i'", result: "0 - 2.5i"},
+        {input: "calc '2i ^ 2'", result: "-4"},
+        {input: "calc '(-4) ^ 0.5'", result: "0 + 2i"},
+        {input: "calc 'ans * ans'", result: "-4"},
+        {input: "calc '-4 ^ 0.5'", result: "-2"},
+    }
+
+    for _, tc := range testCases {
+        val, err := p.Evaluate(tc.input)
+        if err != nil {
+            t.Errorf("Error evaluating %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        if val.String() != tc.result {
+            t.Errorf("Error evaluating %s: expected %s, got %s.\n", tc.input, tc.result, val)
+        }
+    }
+}
+
+func TestParser_Evaluate_Variables(t *testing.T) {
+    l := lexer.New()
+    p := New(l)
+
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        {input: "calc 'rate = 0.07'", result: "0.0700"},
+        {input: "calc 'principal = 1000'", result: "1000.0000"},
+        {input: "calc 'years = 2'", result: "2.0000"},
+        {input: "calc 'principal * (1 + rate) ^ years'", result: "1144.9000"},
+        {input: "calc 'years = years + 1'", result: "3.0000"},
+        {input: "calc 'total = ans * 2'", result: "6.0000"},
+        {input: "calc 'total'", result: "6.0000"},
+    }
+
+    for _, tc := range testCases {
+        val, err := p.Evaluate(tc.input)
+        if err != nil {
+            t.Errorf("Error evaluating %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        if fmt.Sprintf("%.4f", val) != tc.result {
+            t.Errorf("Error evaluating %s: expected %s, got %s.\n", tc.input, tc.result, val)
+        }
+    }
+
+    names := p.Environment().Names()
+    if strings.Join(names, " ") != "principal rate total years" {
+        t.Errorf("Error variables: expected principal rate total years, got %v.\n", names)
+    }
+
+    // Each parser has its own session.
+    other := New(lexer.New())
+    if _, err := other.Evaluate("calc 'rate'"); !errors.Is(err, ErrEquation) {
+        t.Errorf("Error evaluating a variable of another session: expected error %s, got %v.\n", ErrEquation, err)
+    }
+
+    // Variables are converted when the backend changes.
+    p.SetBackend(number.RatBackend)
+    val, err := p.Evaluate("calc 'rate * 3'")
+    if err != nil || val.String() != "0.21" {
+        t.Errorf("Error evaluating rate * 3 with rational backend: expected 0.21, got %v, error %v.\n", val, err)
+    }
+}
+
+func TestParser_Evaluate_Call(t *testing.T) {
+    l := lexer.New()
+    p := New(l)
+
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        {input: "calc 'sqrt(16)'", result: "4.0000"},
+        {input: "calc '2 * sqrt(9) + 1'", result: "7.0000"},
+        {input: "calc 'sqrt(-4)'", result: "0.0000 + 2.0000i"},
+        {input: "calc 'max(1, 7 - 2, 3)'", result: "5.0000"},
+        {input: "calc 'min(4, -2 * 3)'", result: "-6.0000"},
+        {input: "calc 'hypot(3, 4)'", result: "5.0000"},
+        {input: "calc 'abs(-2.5)'", result: "2.5000"},
+        {input: "calc 'floor(-2.5) + ceil(2.1)'", result: "0.0000"},
+        {input: "calc 'round(max[sqrt(2), 1.2])'", result: "1.0000"},
+        {input: "calc 'x = ln(exp(2))'", result: "2.0000"},
+        {input: "calc 'log2(2 ^ x) + log10(1000)'", result: "5.0000"},
+        {input: "calc 'sin(0) + cos(0)'", result: "1.0000"},
+    }
+
+    for _, tc := range testCases {
+        val, err := p.Evaluate(tc.input)
+        if err != nil {
+            t.Errorf("Error evaluating %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        if fmt.Sprintf("%.4f", val) != tc.result {
+            t.Errorf("Error evaluating %s: expected %s, got %.4f.\n", tc.input, tc.result, val)
+        }
+    }
+
+    // Exact backends keep exact results.
+    p.SetBackend(number.RatBackend)
+    val, err := p.Evaluate("calc 'floor(7 / 2) + abs(-1 / 3)'")
+    if err != nil || val.String() != "10/3" {
+        t.Errorf("Error evaluating floor(7 / 2) + abs(-1 / 3) with rational backend: expected 10/3, got %v, error %v.\n", val, err)
+    }
+}
+
+func TestParser_EvaluateEquation(t *testing.T) {
+    p := New(lexer.New())
+
+    val, err := p.EvaluateEquation("5 + 2 * 3")
+    if err != nil || val.Float64() != 11 {
+        t.Errorf("Error evaluating 5 + 2 * 3: expected 11, got %v, error %v.\n", val, err)
+    }
+
+    // Spans are located in the bare equation.
+    _, err = p.EvaluateEquation("(1 + 2")
+    var errList ErrorList
+    if !errors.As(err, &errList) || errList[0].Message != "'(' opened at column 1 is never closed" {
+        t.Errorf("Error evaluating (1 + 2: expected the unclosed bracket at column 1, got %v.\n", err)
+    }
+    _, err = p.EvaluateEquation("1 *")
+    if !errors.As(err, &errList) || errList[0].Span.Offset != 3 {
+        t.Errorf("Error evaluating 1 *: expected an error at the end of the equation, got %v.\n", err)
+    }
+}
+
+func TestParser_ParseEquation(t *testing.T) {
+    p := New(lexer.New())
+
+    // Undefined variables are free variables.
+    n, err := p.ParseEquation("price * (1 + rate)")
+    if err != nil || n.String() != "(* price (+ 1.0000 rate))" {
+        t.Errorf("Error parsing price * (1 + rate): expected (* price (+ 1.0000 rate)), got %v, error %v.\n", n, err)
+    }
+
+    // Evaluated equations still need defined variables.
+    if _, err := p.EvaluateEquation("price"); !errors.Is(err, ErrEquation) {
+        t.Errorf("Error evaluating price: expected error %s, got %v.\n", ErrEquation, err)
+    }
+
+    if _, err := p.ParseEquation("price *"); !errors.Is(err, ErrEquation) {
+        t.Errorf("Error parsing price *: expected error %s, got %v.\n", ErrEquation, err)
+    }
+}
+
+func TestParser_ParseEquation_Encode(t *testing.T) {
+    testCases := []string{
+        "-1 ^ 4",
+        "(-1) ^ 4",
+        "y = max(1, -x, 2.50) / 3i",
+        "+-x",
+    }
+
+    for _, tc := range testCases {
+        for _, backend := range []number.Backend{number.FloatBackend, number.RatBackend, number.NewBigFloatBackend(64)} {
+            p := New(lexer.New())
+            p.SetBackend(backend)
+            n, err := p.ParseEquation(tc)
+            if err != nil {
+                t.Errorf("Error parsing %s: got error %v.\n", tc, err)
+                continue
+            }
+
+            data, err := ast.Encode(n)
+            if err != nil {
+                t.Errorf("Error encoding %s: got error %v.\n", tc, err)
+                continue
+            }
+            decoded, err := ast.Decode(data)
+            if err != nil {
+                t.Errorf("Error decoding %s: got error %v.\n", data, err)
+                continue
+            }
+            if !reflect.DeepEqual(decoded, n) {
+                t.Errorf("Error decoding %s in %s: expected %s, got %s.\n", data, backend, Tree(n), Tree(decoded))
+            }
+        }
+    }
+}
+
+func TestOperatorTables(t *testing.T) {
+    // The operator tables and the operators of package token, which trees are decoded with, are the same operators.
+    for lexicalType := range infixOperators {
+        if !token.IsInfixOperator(lexicalType) {
+            t.Errorf("Error infix operator %s: not an infix operator of package token.\n", lexicalType)
+        }
+    }
+    for lexicalType := range prefixOperators {
+        if !token.IsPrefixOperator(lexicalType) {
+            t.Errorf("Error prefix operator %s: not a prefix operator of package token.\n", lexicalType)
+        }
+    }
+    for _, lexicalType := range []string{token.PLUS, token.MINUS, token.ASTERISK, token.SLASH, token.CIRCUMFLEX, token.DBLSLASH, token.PERCENT, token.REM,
+        token.AMPERSAND, token.PIPE, token.XOR, token.SHL, token.SHR, token.TILDE} {
+        _, infix := infixOperators[lexicalType]
+        _, prefix := prefixOperators[lexicalType]
+        if infix != token.IsInfixOperator(lexicalType) || prefix != token.IsPrefixOperator(lexicalType) {
+            t.Errorf("Error operator %s: the operator tables don't match package token.\n", lexicalType)
+        }
+    }
+}
@@ -0,0 +1,86 @@
+package parser
+
+import (
+    "LexicalCalculator/ast"
+    "fmt"
+    "strings"
+)
+
+// Tree returns a tree as indented ASCII art, one node per line, like
+//
+//  ^
+//  ├── -
+//  │   └── 1
+//  └── 4
+//
+// for '(-1) ^ 4'. Prefix operators have a single child, so '-1 ^ 4' has the '-' at the root instead.
+func Tree(n ast.Node) string {
+    var b strings.Builder
+    b.WriteString(label(n) + "\n")
+    writeChildren(&b, n, "")
+    return b.String()
+}
+
+// writeChildren writes the children of a node, each line starting with the prefix of the node.
+func writeChildren(b *strings.Builder, n ast.Node, prefix string) {
+    nodes := ast.Children(n)
+    for i, child := range nodes {
+        branch, indent := "├── ", "│   "
+        if i == len(nodes)-1 {
+            branch, indent = "└── ", "    "
+        }
+        b.WriteString(prefix + branch + label(child) + "\n")
+        writeChildren(b, child, prefix+indent)
+    }
+}
+
+// DOT returns a tree as a Graphviz DOT digraph, which 'dot -Tpng' draws like the trees in the README.
+// Values and identifiers are boxes, operators, assignments and calls are ellipses with their operands below them.
+func DOT(n ast.Node) string {
+    var b strings.Builder
+    b.WriteString("digraph ast {\n")
+    id := 0
+    var walk func(n ast.Node) int
+    walk = func(n ast.Node) int {
+        node := id
+        id++
+        shape := "box"
+        switch n.(type) {
+        case *ast.Unary, *ast.Binary, *ast.Assignment, *ast.Call:
+            shape = "ellipse"
+        }
+        fmt.Fprintf(&b, "    node%d [label=\"%s\", shape=%s];\n", node, escapeDOT(label(n)), shape)
+        for _, child := range ast.Children(n) {
+            fmt.Fprintf(&b, "    node%d -> node%d;\n", node, walk(child))
+        }
+        return node
+    }
+    walk(n)
+    b.WriteString("}\n")
+    return b.String()
+}
+
+// label returns the text of a node in a drawn tree, its children are drawn separately.
+func label(n ast.Node) string {
+    switch n := n.(type) {
+    case *ast.Identifier:
+        return n.Name
+    case *ast.Literal:
+        return formatValue(n)
+    case *ast.Assignment:
+        return "="
+    case *ast.Call:
+        return n.Name + "()"
+    case *ast.Unary:
+        return n.Operator
+    case *ast.Binary:
+        return n.Operator
+    default:
+        return "0"
+    }
+}
+
+// escapeDOT escapes a label for a quoted DOT string.
+func escapeDOT(s string) string {
+    return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
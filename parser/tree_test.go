@@ -0,0 +1,68 @@
+package parser
+
+import (
+    "LexicalCalculator/lexer"
+    "testing"
+)
+
+func TestTree(t *testing.T) {
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        {input: "-1 ^ 4", result: "-\n└── ^\n    ├── 1\n    └── 4\n"},
+        {input: "(-1) ^ 4", result: "^\n├── -\n│   └── 1\n└── 4\n"},
+        {input: "y = max(1, x * 2.50)", result: "=\n├── y\n└── max()\n    ├── 1\n    └── *\n        ├── x\n        └── 2.50\n"},
+        {input: "x", result: "x\n"},
+    }
+
+    for _, tc := range testCases {
+        n, err := New(lexer.New()).ParseEquation(tc.input)
+        if err != nil {
+            t.Errorf("Error parsing %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        if s := Tree(n); s != tc.result {
+            t.Errorf("Error drawing %s: expected\n%s, got\n%s.\n", tc.input, tc.result, s)
+        }
+    }
+}
+
+func TestDOT(t *testing.T) {
+    testCases := []struct {
+        input  string
+        result string
+    }{
+        {
+            input: "(-1) ^ x",
+            result: "digraph ast {\n" +
+                "    node0 [label=\"^\", shape=ellipse];\n" +
+                "    node1 [label=\"-\", shape=ellipse];\n" +
+                "    node2 [label=\"1\", shape=box];\n" +
+                "    node1 -> node2;\n" +
+                "    node0 -> node1;\n" +
+                "    node3 [label=\"x\", shape=box];\n" +
+                "    node0 -> node3;\n" +
+                "}\n",
+        },
+        {
+            input: "sqrt(2)",
+            result: "digraph ast {\n" +
+                "    node0 [label=\"sqrt()\", shape=ellipse];\n" +
+                "    node1 [label=\"2\", shape=box];\n" +
+                "    node0 -> node1;\n" +
+                "}\n",
+        },
+    }
+
+    for _, tc := range testCases {
+        n, err := New(lexer.New()).ParseEquation(tc.input)
+        if err != nil {
+            t.Errorf("Error parsing %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        if s := DOT(n); s != tc.result {
+            t.Errorf("Error drawing %s: expected\n%s, got\n%s.\n", tc.input, tc.result, s)
+        }
+    }
+}
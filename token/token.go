@@ -1,8 +1,11 @@
 package token
 
+import "fmt"
+
 const (
-    CALC = "CALC"
-    ANS  = "ANS"
+    CALC  = "CALC"
+    ANS   = "ANS"
+    IDENT = "IDENT"
 
     SINGLEQUOTE = "'"
     LPAREN      = "("
@@ -14,21 +17,74 @@ const (
 
     INT   = "INT"
     FLOAT = "FLOAT"
+    IMAG  = "IMAG"
 
     PLUS       = "+"
     MINUS      = "-"
     ASTERISK   = "*"
     SLASH      = "/"
     CIRCUMFLEX = "^"
+    PERCENT    = "%"
+    DBLSLASH   = "//"
+    REM        = "rem"
+    AMPERSAND  = "&"
+    PIPE       = "|"
+    XOR        = "xor"
+    SHL        = "<<"
+    SHR        = ">>"
+    TILDE      = "~"
+
+    ASSIGN = "="
+    COMMA  = ","
 
     UNKNOWN = "UNKNOWN"
     EOF     = "EOF"
 )
 
+var (
+    // prefixOperators stores the lexical types of the prefix operators, like '-' in '-x'.
+    prefixOperators = map[string]struct{}{PLUS: {}, MINUS: {}, TILDE: {}}
+    // infixOperators stores the lexical types of the infix operators, like '-' in 'x - 1'.
+    infixOperators = map[string]struct{}{PLUS: {}, MINUS: {}, SLASH: {}, ASTERISK: {}, CIRCUMFLEX: {}, PERCENT: {}, DBLSLASH: {}, REM: {},
+        AMPERSAND: {}, PIPE: {}, XOR: {}, SHL: {}, SHR: {}}
+)
+
+// IsPrefixOperator checks whether a lexical type is a prefix operator.
+func IsPrefixOperator(lexicalType string) bool {
+    _, ok := prefixOperators[lexicalType]
+    return ok
+}
+
+// IsInfixOperator checks whether a lexical type is an infix operator.
+func IsInfixOperator(lexicalType string) bool {
+    _, ok := infixOperators[lexicalType]
+    return ok
+}
+
+// Span is the location of a token in the input.
+// Offset and Length are counted in bytes, Line and Column start from 1.
+type Span struct {
+    Offset int
+    Line   int
+    Column int
+    Length int
+}
+
+// End returns the byte offset right after the last character of the span.
+func (s Span) End() int {
+    return s.Offset + s.Length
+}
+
+// String returns the human-readable position of a span.
+func (s Span) String() string {
+    return fmt.Sprintf("line %d, column %d", s.Line, s.Column)
+}
+
 // Token is the result of after parsing input with a lexer.
 type Token struct {
     Literal     string
     LexicalType string
+    Span        Span
 }
 
 // New creates a new Token.
@@ -21,3 +21,26 @@ func TestNew(t *testing.T) {
         t.Errorf("Error token literal: expected %s, got %s.\n", testCase.Literal, tok.Literal)
     }
 }
+
+func TestIsOperator(t *testing.T) {
+    testCases := []struct {
+        lexicalType string
+        prefix      bool
+        infix       bool
+    }{
+        {lexicalType: MINUS, prefix: true, infix: true},
+        {lexicalType: TILDE, prefix: true, infix: false},
+        {lexicalType: REM, prefix: false, infix: true},
+        {lexicalType: ASSIGN, prefix: false, infix: false},
+        {lexicalType: "mod", prefix: false, infix: false},
+    }
+
+    for _, tc := range testCases {
+        if IsPrefixOperator(tc.lexicalType) != tc.prefix {
+            t.Errorf("Error prefix operator %s: expected %t, got %t.\n", tc.lexicalType, tc.prefix, !tc.prefix)
+        }
+        if IsInfixOperator(tc.lexicalType) != tc.infix {
+            t.Errorf("Error infix operator %s: expected %t, got %t.\n", tc.lexicalType, tc.infix, !tc.infix)
+        }
+    }
+}
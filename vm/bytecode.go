@@ -0,0 +1,269 @@
+/*
+Package vm lowers ast trees to bytecode, and runs the bytecode on a stack machine in float64.
+
+Each Instruction is a 32-bit word, an Opcode in the low byte and an operand in the upper 24 bits.
+The operand indexes the constants, variables or calls of the Bytecode, depending on the Opcode.
+*/
+package vm
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/function"
+    "LexicalCalculator/number"
+    "errors"
+    "fmt"
+    "strings"
+)
+
+// Opcode is the operation of an Instruction.
+type Opcode byte
+
+const (
+    // OpConst pushes Constants[operand].
+    OpConst Opcode = iota
+    // OpLoad pushes the value of Variables[operand].
+    OpLoad
+    // OpNeg pops x and pushes -x.
+    OpNeg
+    // OpNot pops x and pushes the bitwise complement ~x.
+    OpNot
+    // OpAdd, OpSub, OpMul, OpQuo, OpPow, OpFloorQuo, OpMod and OpRem pop y, then x, and push x op y.
+    OpAdd
+    OpSub
+    OpMul
+    OpQuo
+    OpPow
+    OpFloorQuo
+    OpMod
+    OpRem
+    // OpAnd, OpOr, OpXor, OpShl and OpShr pop y, then x, and push the bitwise x op y of their 64-bit integers.
+    OpAnd
+    OpOr
+    OpXor
+    OpShl
+    OpShr
+    // OpCall pops the arguments of Calls[operand] and pushes the result of the call.
+    OpCall
+)
+
+// maxOperand is the largest operand an Instruction can hold.
+const maxOperand = 1<<24 - 1
+
+var (
+    ErrNotCompilable = errors.New("error equation can't be compiled")
+)
+
+var opcodeNames = [...]string{OpConst: "CONST", OpLoad: "LOAD", OpNeg: "NEG", OpNot: "NOT", OpAdd: "ADD", OpSub: "SUB", OpMul: "MUL", OpQuo: "QUO", OpPow: "POW", OpFloorQuo: "FLOORQUO", OpMod: "MOD", OpRem: "REM", OpAnd: "AND", OpOr: "OR", OpXor: "XOR", OpShl: "SHL", OpShr: "SHR", OpCall: "CALL"}
+
+// binaryOpcodes stores the Opcode of each binary operator.
+var binaryOpcodes = map[string]Opcode{"+": OpAdd, "-": OpSub, "*": OpMul, "/": OpQuo, "^": OpPow, "//": OpFloorQuo, "%": OpMod, "rem": OpRem,
+    "&": OpAnd, "|": OpOr, "xor": OpXor, "<<": OpShl, ">>": OpShr}
+
+// String returns the mnemonic of an Opcode.
+func (op Opcode) String() string {
+    if int(op) < len(opcodeNames) {
+        return opcodeNames[op]
+    }
+    return fmt.Sprintf("OP(%d)", byte(op))
+}
+
+// Instruction is an Opcode and its operand.
+type Instruction uint32
+
+// NewInstruction creates a new Instruction.
+func NewInstruction(op Opcode, operand int) Instruction {
+    return Instruction(uint32(operand)<<8 | uint32(op))
+}
+
+// Op returns the Opcode of the Instruction.
+func (i Instruction) Op() Opcode {
+    return Opcode(i & 0xff)
+}
+
+// Operand returns the operand of the Instruction.
+func (i Instruction) Operand() int {
+    return int(i >> 8)
+}
+
+// Variable is a variable loaded by OpLoad.
+// Its value is bound when the bytecode runs, Default is used if there is no binding and HasDefault is set.
+type Variable struct {
+    Name       string
+    Default    float64
+    HasDefault bool
+}
+
+// Call is a function call made by OpCall with the Argc topmost values of the stack.
+type Call struct {
+    Function *function.Function
+    Argc     int
+}
+
+// Bytecode is an equation lowered to Instructions.
+// Bytecode is immutable once compiled, it can be run by multiple goroutines at once.
+type Bytecode struct {
+    Instructions []Instruction
+    Constants    []float64
+    Variables    []Variable
+    Calls        []Call
+    // StackSize is the number of values the stack needs to run the bytecode.
+    StackSize int
+}
+
+// String returns the disassembly of the bytecode, one Instruction per line.
+func (b *Bytecode) String() string {
+    var sb strings.Builder
+    for _, ins := range b.Instructions {
+        sb.WriteString(ins.Op().String())
+        switch ins.Op() {
+        case OpConst:
+            fmt.Fprintf(&sb, " %g", b.Constants[ins.Operand()])
+        case OpLoad:
+            fmt.Fprintf(&sb, " %s", b.Variables[ins.Operand()].Name)
+        case OpCall:
+            call := b.Calls[ins.Operand()]
+            fmt.Fprintf(&sb, " %s %d", call.Function.Name, call.Argc)
+        }
+        sb.WriteString("\n")
+    }
+    return sb.String()
+}
+
+// Compile lowers an ast tree to Bytecode.
+// Constants and functions are resolved in the Environment, other identifiers are variables bound when the bytecode runs.
+// Variables defined in the Environment default to their current value.
+// Assignments and complex numbers can't be compiled.
+func Compile(n ast.Node, env *ast.Environment) (*Bytecode, error) {
+    c := &compiler{
+        code:      &Bytecode{},
+        env:       env,
+        constants: make(map[float64]int),
+        variables: make(map[string]int),
+    }
+    if err := c.compile(n); err != nil {
+        return nil, err
+    }
+    return c.code, nil
+}
+
+// compiler is the Visitor emitting the Instructions of the Nodes of a tree.
+// It keeps track of the stack depth and of the operands already assigned while compiling.
+type compiler struct {
+    code      *Bytecode
+    env       *ast.Environment
+    depth     int
+    constants map[float64]int
+    variables map[string]int
+}
+
+// compile emits the Instructions of a node, which leave its value on top of the stack.
+func (c *compiler) compile(n ast.Node) error {
+    if n == nil {
+        return c.emitConst(0)
+    }
+    return n.Accept(c)
+}
+
+func (c *compiler) VisitLiteral(n *ast.Literal) error {
+    if cmplx, ok := n.Value.(number.Complex); ok && imag(cmplx) != 0 {
+        return fmt.Errorf("%w: complex number %s", ErrNotCompilable, n.Value)
+    }
+    return c.emitConst(n.Value.Float64())
+}
+
+// VisitIdentifier emits a constant as its value, and any other identifier as a variable.
+func (c *compiler) VisitIdentifier(n *ast.Identifier) error {
+    value, defined := c.env.Get(n.Name)
+    if defined && c.env.IsConstant(n.Name) {
+        return c.emitConst(value.Float64())
+    }
+
+    index, ok := c.variables[n.Name]
+    if !ok {
+        index = len(c.code.Variables)
+        v := Variable{Name: n.Name, HasDefault: defined}
+        if defined {
+            v.Default = value.Float64()
+        }
+        c.code.Variables = append(c.code.Variables, v)
+        c.variables[n.Name] = index
+    }
+    return c.emit(OpLoad, index, 1)
+}
+
+func (c *compiler) VisitAssignment(n *ast.Assignment) error {
+    return fmt.Errorf("%w: assignment to '%s'", ErrNotCompilable, n.Target.Name)
+}
+
+// VisitCall emits the arguments of a call, then the call itself.
+func (c *compiler) VisitCall(n *ast.Call) error {
+    f, ok := c.env.Function(n.Name)
+    if !ok {
+        return fmt.Errorf("%w '%s'", function.ErrUnknownFunction, n.Name)
+    }
+    if err := f.CheckArity(len(n.Args)); err != nil {
+        return err
+    }
+
+    for _, arg := range n.Args {
+        if err := c.compile(arg); err != nil {
+            return err
+        }
+    }
+    c.code.Calls = append(c.code.Calls, Call{Function: f, Argc: len(n.Args)})
+    // The arguments are replaced by the result.
+    return c.emit(OpCall, len(c.code.Calls)-1, 1-len(n.Args))
+}
+
+// VisitUnary emits the operand of a prefix operator, then the operator itself.
+func (c *compiler) VisitUnary(n *ast.Unary) error {
+    if err := c.compile(n.Operand); err != nil {
+        return err
+    }
+    switch n.Operator {
+    case "-":
+        return c.emit(OpNeg, 0, 0)
+    case "~":
+        return c.emit(OpNot, 0, 0)
+    }
+    return nil
+}
+
+// VisitBinary emits the operands of an infix operator, then the operator itself.
+func (c *compiler) VisitBinary(n *ast.Binary) error {
+    op, ok := binaryOpcodes[n.Operator]
+    if !ok {
+        return fmt.Errorf("%w: unknown operator '%s'", ErrNotCompilable, n.Operator)
+    }
+    if err := c.compile(n.Left); err != nil {
+        return err
+    }
+    if err := c.compile(n.Right); err != nil {
+        return err
+    }
+    return c.emit(op, 0, -1)
+}
+
+// emitConst emits an OpConst, equal constants share their operand.
+func (c *compiler) emitConst(value float64) error {
+    index, ok := c.constants[value]
+    if !ok {
+        index = len(c.code.Constants)
+        c.code.Constants = append(c.code.Constants, value)
+        c.constants[value] = index
+    }
+    return c.emit(OpConst, index, 1)
+}
+
+// emit appends an Instruction, which changes the depth of the stack by the given effect.
+func (c *compiler) emit(op Opcode, operand int, effect int) error {
+    if operand > maxOperand {
+        return fmt.Errorf("%w: more than %d operands", ErrNotCompilable, maxOperand)
+    }
+    c.code.Instructions = append(c.code.Instructions, NewInstruction(op, operand))
+    c.depth += effect
+    if c.depth > c.code.StackSize {
+        c.code.StackSize = c.depth
+    }
+    return nil
+}
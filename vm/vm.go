@@ -0,0 +1,168 @@
+package vm
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/number"
+    "fmt"
+    "math"
+)
+
+// Run runs the bytecode with the values of its variables, and returns the value left on the stack.
+// The stack holds the intermediate values, it must have room for at least StackSize values.
+// Run doesn't allocate, unless it fails or calls a function without a float64 implementation.
+func Run(code *Bytecode, bindings map[string]float64, stack []float64) (float64, error) {
+    if len(stack) < code.StackSize {
+        return 0, fmt.Errorf("%w: stack of %d values, needs %d", ErrNotCompilable, len(stack), code.StackSize)
+    }
+
+    sp := 0
+    for _, ins := range code.Instructions {
+        switch ins.Op() {
+        case OpConst:
+            stack[sp] = code.Constants[ins.Operand()]
+            sp++
+        case OpLoad:
+            v := &code.Variables[ins.Operand()]
+            value, ok := bindings[v.Name]
+            if !ok {
+                if !v.HasDefault {
+                    return 0, fmt.Errorf("%w '%s'", ast.ErrUndefinedVariable, v.Name)
+                }
+                value = v.Default
+            }
+            stack[sp] = value
+            sp++
+        case OpNeg:
+            // Like number.Float, the negation of 0 is 0 instead of -0.
+            stack[sp-1] = 0 - stack[sp-1]
+        case OpNot:
+            x, err := toInt64(OpNot, stack[sp-1])
+            if err != nil {
+                return 0, err
+            }
+            stack[sp-1] = float64(^x)
+        case OpAdd:
+            sp--
+            stack[sp-1] += stack[sp]
+        case OpSub:
+            sp--
+            stack[sp-1] -= stack[sp]
+        case OpMul:
+            sp--
+            stack[sp-1] *= stack[sp]
+        case OpQuo:
+            sp--
+            if stack[sp] == 0 {
+                return 0, ast.ErrZeroDivision
+            }
+            stack[sp-1] /= stack[sp]
+        case OpPow:
+            sp--
+            x, y := stack[sp-1], stack[sp]
+            result := math.Pow(x, y)
+            // The power of a negative base and a fractional exponent is complex.
+            if math.IsNaN(result) && !math.IsNaN(x) && !math.IsNaN(y) {
+                return 0, number.ErrNotReal
+            }
+            stack[sp-1] = result
+        case OpFloorQuo, OpMod, OpRem:
+            sp--
+            x, y := stack[sp-1], stack[sp]
+            if y == 0 {
+                return 0, ast.ErrZeroDivision
+            }
+            // Like number.Float, the modulo has the sign of the divisor and the remainder the sign of the dividend.
+            switch ins.Op() {
+            case OpFloorQuo:
+                stack[sp-1] = number.FloorQuoFloat64(x, y)
+            case OpMod:
+                stack[sp-1] = number.ModFloat64(x, y)
+            default:
+                stack[sp-1] = math.Mod(x, y)
+            }
+        case OpAnd, OpOr, OpXor, OpShl, OpShr:
+            sp--
+            result, err := bitwise(ins.Op(), stack[sp-1], stack[sp])
+            if err != nil {
+                return 0, err
+            }
+            stack[sp-1] = result
+        case OpCall:
+            call := &code.Calls[ins.Operand()]
+            sp -= call.Argc
+            result, err := call.Function.CallReal(stack[sp : sp+call.Argc])
+            if err != nil {
+                return 0, err
+            }
+            stack[sp] = result
+            sp++
+        default:
+            return 0, fmt.Errorf("%w: unknown opcode %s", ErrNotCompilable, ins.Op())
+        }
+    }
+
+    if sp == 0 {
+        return 0, nil
+    }
+    return stack[sp-1], nil
+}
+
+// bitwise returns the bitwise x op y of the 64-bit integers of x and y, which fails like it does in ast.Evaluate.
+func bitwise(op Opcode, x float64, y float64) (float64, error) {
+    a, err := toInt64(op, x)
+    if err != nil {
+        return 0, err
+    }
+    b, err := toInt64(op, y)
+    if err != nil {
+        return 0, err
+    }
+
+    switch op {
+    case OpAnd:
+        return float64(a & b), nil
+    case OpOr:
+        return float64(a | b), nil
+    case OpXor:
+        return float64(a ^ b), nil
+    }
+    if b < 0 {
+        return 0, number.ErrShiftCount
+    }
+    if op == OpShr {
+        return float64(a >> b), nil
+    }
+    // Like number.Int, shifting bits out is an overflow.
+    if a != 0 && (b >= 64 || (a<<b)>>b != a) {
+        return 0, number.ErrOverflow
+    }
+    return float64(a << b), nil
+}
+
+// toInt64 converts an operand of a bitwise Opcode into an int64.
+// Operands which aren't integers are rejected instead of truncated.
+func toInt64(op Opcode, x float64) (int64, error) {
+    err := number.ErrNotAnInteger
+    switch {
+    case x != math.Trunc(x):
+    case x < -(1<<63) || x >= 1<<63:
+        // -2^63 is the smallest int64 and exact in float64, 2^63 is one past the largest.
+        err = number.ErrOverflow
+    default:
+        return int64(x), nil
+    }
+    return 0, fmt.Errorf("%w: '%s' needs 64-bit integer operands, got %g", err, operatorOf(op), x)
+}
+
+// operatorOf returns the operator of an Opcode, for error messages.
+func operatorOf(op Opcode) string {
+    if op == OpNot {
+        return "~"
+    }
+    for operator, opcode := range binaryOpcodes {
+        if opcode == op {
+            return operator
+        }
+    }
+    return op.String()
+}
@@ -0,0 +1,160 @@
+package vm
+
+import (
+    "LexicalCalculator/ast"
+    "LexicalCalculator/lexer"
+    "LexicalCalculator/number"
+    "LexicalCalculator/parser"
+    "LexicalCalculator/support"
+    "errors"
+    "testing"
+)
+
+// parse parses a bare equation, undefined variables are free variables.
+func parse(t testing.TB, equation string) ast.Node {
+    n, err := parser.New(lexer.New()).ParseEquation(equation)
+    if err != nil {
+        t.Fatalf("Error parsing %s: got error %v.\n", equation, err)
+    }
+    return n
+}
+
+func TestCompile(t *testing.T) {
+    env := ast.NewEnvironment()
+    env.SetConstant("g", number.Float(9.81))
+    env.Set("rate", number.Float(0.07))
+
+    testCases := []struct {
+        input     string
+        code      string
+        stackSize int
+    }{
+        {input: "1 + 2 * 3", code: "CONST 1\nCONST 2\nCONST 3\nMUL\nADD\n", stackSize: 3},
+        {input: "-x ^ 2", code: "LOAD x\nCONST 2\nPOW\nNEG\n", stackSize: 2},
+        {input: "+x - x", code: "LOAD x\nLOAD x\nSUB\n", stackSize: 2},
+        {input: "g * rate", code: "CONST 9.81\nLOAD rate\nMUL\n", stackSize: 2},
+        {input: "max(1, 2, sqrt(4)) / 2", code: "CONST 1\nCONST 2\nCONST 4\nCALL sqrt 1\nCALL max 3\nCONST 2\nQUO\n", stackSize: 3},
+    }
+
+    for _, tc := range testCases {
+        code, err := Compile(parse(t, tc.input), env)
+        if err != nil {
+            t.Errorf("Error compiling %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        if code.String() != tc.code {
+            t.Errorf("Error compiling %s: expected\n%s, got\n%s.\n", tc.input, tc.code, code)
+        }
+        if code.StackSize != tc.stackSize {
+            t.Errorf("Error stack size of %s: expected %d, got %d.\n", tc.input, tc.stackSize, code.StackSize)
+        }
+    }
+
+    for _, input := range []string{"x = 1", "1 + 2i"} {
+        if _, err := Compile(parse(t, input), env); !errors.Is(err, ErrNotCompilable) {
+            t.Errorf("Error compiling %s: expected error %s, got %v.\n", input, ErrNotCompilable, err)
+        }
+    }
+}
+
+func TestRun(t *testing.T) {
+    env := ast.NewEnvironment()
+    env.Set("rate", number.Float(0.07))
+
+    testCases := []struct {
+        input    string
+        bindings map[string]float64
+        result   float64
+        err      error
+    }{
+        {input: "{[(1 + 2) * 3] * [(100 / 20) + 8]} - 123", result: -6},
+        {input: "2 ^ -1 - -x", bindings: map[string]float64{"x": 1}, result: 1.5},
+        {input: "principal * (1 + rate) ^ 2", bindings: map[string]float64{"principal": 1000}, result: 1144.9},
+        {input: "rate * 2", bindings: map[string]float64{"rate": 1}, result: 2},
+        {input: "hypot(x, 4) + min(x)", bindings: map[string]float64{"x": 3}, result: 8},
+        {input: "-0", result: 0},
+        {input: "x / 0", bindings: map[string]float64{"x": 1}, err: ast.ErrZeroDivision},
+        {input: "-17 % 5 + 17 mod -5", result: 0},
+        {input: "-17 rem 5 - 7.5 // 2", result: -5},
+        {input: "1 // 0.1", result: 9},
+        {input: "1 // 0.1", result: 9},
+        {input: "x % 0", bindings: map[string]float64{"x": 1}, err: ast.ErrZeroDivision},
+        {input: "x // 0", bindings: map[string]float64{"x": 1}, err: ast.ErrZeroDivision},
+        {input: "x rem 0", bindings: map[string]float64{"x": 1}, err: ast.ErrZeroDivision},
+        {input: "~x & 255 | 1 << 8 xor -16 >> 2", bindings: map[string]float64{"x": 5}, result: -258},
+        {input: "x & 1", bindings: map[string]float64{"x": 2.5}, err: number.ErrNotAnInteger},
+        {input: "~x", bindings: map[string]float64{"x": 1e19}, err: number.ErrOverflow},
+        {input: "1 << x", bindings: map[string]float64{"x": 63}, err: number.ErrOverflow},
+        {input: "1 >> x", bindings: map[string]float64{"x": -1}, err: number.ErrShiftCount},
+        {input: "x + y", bindings: map[string]float64{"x": 1}, err: ast.ErrUndefinedVariable},
+        {input: "(-8) ^ (1 / 3)", err: number.ErrNotReal},
+        {input: "ln(-1)", err: number.ErrNotReal},
+    }
+
+    for _, tc := range testCases {
+        code, err := Compile(parse(t, tc.input), env)
+        if err != nil {
+            t.Errorf("Error compiling %s: got error %v.\n", tc.input, err)
+            continue
+        }
+        re, err := Run(code, tc.bindings, make([]float64, code.StackSize))
+        if !errors.Is(err, tc.err) {
+            t.Errorf("Error running %s: expected error %v, got %v.\n", tc.input, tc.err, err)
+            continue
+        }
+        if err == nil && !support.AlmostEqual(re, tc.result, 0.0001) {
+            t.Errorf("Error running %s: expected %f, got %f.\n", tc.input, tc.result, re)
+        }
+    }
+
+    code, _ := Compile(parse(t, "1 + 2"), nil)
+    if _, err := Run(code, nil, nil); !errors.Is(err, ErrNotCompilable) {
+        t.Errorf("Error running with a small stack: expected error %s, got %v.\n", ErrNotCompilable, err)
+    }
+}
+
+// benchmarkEquation is evaluated by both the tree-walker and the VM.
+const benchmarkEquation = "{[(12 + 3) * 6] + 1} * 2 - 2 ^ -1 * (100 / 20) + [(1 + 2) * 3] * 4"
+
+func BenchmarkEvaluate(b *testing.B) {
+    n := parse(b, benchmarkEquation)
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        ast.Evaluate(n)
+    }
+}
+
+func BenchmarkRun(b *testing.B) {
+    code, _ := Compile(parse(b, benchmarkEquation), nil)
+    stack := make([]float64, code.StackSize)
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        Run(code, nil, stack)
+    }
+}
+
+func BenchmarkEvaluateIn_Variables(b *testing.B) {
+    n := parse(b, "principal * (1 + rate / 12) ^ (12 * years) - principal")
+    env := ast.NewEnvironment()
+    env.Set("principal", number.Float(1000))
+    env.Set("rate", number.Float(0.05))
+    env.Set("years", number.Float(10))
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        ast.EvaluateIn(n, env)
+    }
+}
+
+func BenchmarkRun_Variables(b *testing.B) {
+    code, _ := Compile(parse(b, "principal * (1 + rate / 12) ^ (12 * years) - principal"), nil)
+    bindings := map[string]float64{"principal": 1000, "rate": 0.05, "years": 10}
+    stack := make([]float64, code.StackSize)
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        Run(code, bindings, stack)
+    }
+}